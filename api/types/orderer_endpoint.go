@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"slices"
 	"strconv"
 	"strings"
@@ -92,7 +93,39 @@ func (e *OrdererEndpoint) SupportsAPI(api string) bool {
 // Schema 1: YAML.
 // Schema 2: JSON.
 // Schema 3: [id=ID,][msp-id=MspID,][broadcast,][deliver,][host=Host,][port=Port,][Host:Port].
+// IPv6 hosts are given in brackets in the Host:Port form (e.g., [::1]:7050), and may be given with or without
+// brackets otherwise. The host is normalized, i.e., stored without brackets and, if it is an IP, in its canonical form.
 func ParseOrdererEndpoint(valueRaw string) (*OrdererEndpoint, error) {
+	ret, err := parseOrdererEndpoint(valueRaw)
+	if err != nil {
+		return ret, err
+	}
+	ret.Host = normalizeHost(ret.Host)
+	return ret, nil
+}
+
+// NormalizeAddress returns the Host:Port address with its host normalized, such that equivalent addresses
+// are equal, e.g., [0:0::1]:7050 becomes [::1]:7050. Addresses that are not of the Host:Port form are returned as is.
+func NormalizeAddress(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return net.JoinHostPort(normalizeHost(host), port)
+}
+
+// normalizeHost removes the brackets of an IPv6 host, and puts IP hosts in their canonical form.
+func normalizeHost(host string) string {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return addr.String()
+	}
+	return host
+}
+
+func parseOrdererEndpoint(valueRaw string) (*OrdererEndpoint, error) {
 	ret := &OrdererEndpoint{ID: NoID}
 	if len(valueRaw) == 0 {
 		return ret, nil
@@ -68,3 +68,43 @@ port: 5050
 		Port:  5050,
 	}, e)
 }
+
+func TestIPv6(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		value   string
+		host    string
+		address string
+	}{
+		{value: "[::1]:7050", host: "::1", address: "[::1]:7050"},
+		{value: "id=1,msp-id=org,broadcast,[2001:db8::1]:7050", host: "2001:db8::1", address: "[2001:db8::1]:7050"},
+		{value: "[0:0:0:0:0:0:0:1]:7050", host: "::1", address: "[::1]:7050"},
+		{value: "[FE80::1%eth0]:7050", host: "fe80::1%eth0", address: "[fe80::1%eth0]:7050"},
+		{value: "host=::1,port=7050", host: "::1", address: "[::1]:7050"},
+		{value: "host=[::1],port=7050", host: "::1", address: "[::1]:7050"},
+		{value: `{"host":"[::1]","port":7050}`, host: "::1", address: "[::1]:7050"},
+		{value: "host: '::1'\nport: 7050\n", host: "::1", address: "[::1]:7050"},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Parallel()
+			e, err := ParseOrdererEndpoint(tc.value)
+			require.NoError(t, err)
+			require.Equal(t, tc.host, e.Host)
+			require.Equal(t, 7050, e.Port)
+			require.Equal(t, tc.address, e.Address())
+
+			// The string form parses back to the same endpoint.
+			roundTrip, err := ParseOrdererEndpoint(e.String())
+			require.NoError(t, err)
+			require.Equal(t, e, roundTrip)
+		})
+	}
+
+	_, err := ParseOrdererEndpoint("::1:7050")
+	require.ErrorContains(t, err, "too many colons")
+
+	require.Equal(t, "[::1]:7050", NormalizeAddress("[0:0::1]:7050"))
+	require.Equal(t, "127.0.0.1:7050", NormalizeAddress("127.0.0.1:7050"))
+	require.Equal(t, "localhost:7050", NormalizeAddress("localhost:7050"))
+	require.Equal(t, "org1-address1", NormalizeAddress("org1-address1"))
+}
@@ -9,6 +9,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"runtime"
 	"strings"
@@ -33,7 +34,7 @@ func main() {
 	var outputBlock, outputChannelCreateTx, channelCreateTxBaseProfile, profile, configPath, channelID, inspectBlock, inspectChannelCreateTx, asOrg, printOrg string
 
 	flag.StringVar(&outputBlock, "outputBlock", "", "The path to write the genesis block to (if set)")
-	flag.StringVar(&channelID, "channelID", "", "The channel ID to use in the configtx")
+	flag.StringVar(&channelID, "channelID", "", "The channel ID to use in the configtx, and to expect in the block to inspect")
 	flag.StringVar(&outputChannelCreateTx, "outputCreateChannelTx", "", "[DEPRECATED] The path to write a channel creation configtx to (if set)")
 	flag.StringVar(&channelCreateTxBaseProfile, "channelCreateTxBaseProfile", "", "[DEPRECATED] Specifies a profile to consider as the orderer system channel current state to allow modification of non-application parameters during channel create tx generation. Only valid in conjunction with 'outputCreateChannelTx'.")
 	flag.StringVar(&profile, "profile", "", "The profile from configtx.yaml to use for generation.")
@@ -43,6 +44,10 @@ func main() {
 	flag.StringVar(&asOrg, "asOrg", "", "Performs the config generation as a particular organization (by name), only including values in the write set that org (likely) has privilege to set")
 	flag.StringVar(&printOrg, "printOrg", "", "Prints the definition of an organization as JSON. (useful for adding an org to a channel manually)")
 
+	autoCapabilities := flag.Bool("autoCapabilities", false, "Enables the capabilities required by the orderer type of the profile, e.g., V3_0 for BFT")
+	batchMaxMessageCount := flag.Uint("batchMaxMessageCount", 0, "Overrides the orderer BatchSize.MaxMessageCount of the profile (if set)")
+	batchAbsoluteMaxBytes := flag.String("batchAbsoluteMaxBytes", "", "Overrides the orderer BatchSize.AbsoluteMaxBytes of the profile, e.g., '10 MB' (if set)")
+	batchPreferredMaxBytes := flag.String("batchPreferredMaxBytes", "", "Overrides the orderer BatchSize.PreferredMaxBytes of the profile, e.g., '2 MB' (if set)")
 	versionCmd := flag.Bool("version", false, "Show version information")
 
 	flag.Parse()
@@ -92,6 +97,19 @@ func main() {
 		} else {
 			profileConfig = configtxgen.Load(profile)
 		}
+		if *autoCapabilities {
+			configtxgen.DeriveCapabilities(profileConfig)
+		}
+		if *batchMaxMessageCount > 0 || *batchAbsoluteMaxBytes != "" || *batchPreferredMaxBytes != "" {
+			if *batchMaxMessageCount > math.MaxUint32 {
+				logger.Fatalf("The '-batchMaxMessageCount' must not exceed %d", uint32(math.MaxUint32))
+			}
+			err := configtxgen.OverrideBatchSize(profileConfig, uint32(*batchMaxMessageCount),
+				*batchAbsoluteMaxBytes, *batchPreferredMaxBytes)
+			if err != nil {
+				logger.Fatalf("Error overriding the batch size: %s", err)
+			}
+		}
 	}
 
 	var baseProfile *configtxgen.Profile
@@ -119,7 +137,7 @@ func main() {
 	}
 
 	if inspectBlock != "" {
-		if err := configtxgen.DoInspectBlock(inspectBlock); err != nil {
+		if err := configtxgen.DoInspectChannelBlock(inspectBlock, channelID); err != nil {
 			logger.Fatalf("Error on inspectBlock: %s", err)
 		}
 	}
@@ -14,9 +14,12 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/hyperledger/fabric-lib-go/bccsp/factory"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
 	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+	"github.com/hyperledger/fabric-x-common/protoutil"
 	"github.com/hyperledger/fabric-x-common/tools/configtxgen"
 )
 
@@ -70,6 +73,31 @@ func TestBlockFlags(t *testing.T) {
 	require.NoError(t, err, "Block file is written successfully")
 }
 
+func TestAutoCapabilitiesFlag(t *testing.T) {
+	blockDest := filepath.Join(t.TempDir(), "block")
+	oldArgs := os.Args
+	defer func() {
+		os.Args = oldArgs
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	}()
+	os.Args = []string{
+		"cmd",
+		"-channelID=testchannelid",
+		"-profile=" + configtxgen.SampleAppChannelSmartBftProfile,
+		"-outputBlock=" + blockDest,
+		"-autoCapabilities",
+	}
+	configtest.SetDevFabricConfigPath(t)
+
+	main()
+
+	block, err := protoutil.ReadBlockFromFile(blockDest)
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromBlock(block, factory.GetDefault())
+	require.NoError(t, err)
+	require.True(t, bundle.ChannelConfig().Capabilities().ConsensusTypeBFT())
+}
+
 func TestGetVersionInfo(t *testing.T) {
 	t.Parallel()
 	testSHAs := []string{"", "abcdefg"}
@@ -83,3 +111,36 @@ func TestGetVersionInfo(t *testing.T) {
 		require.Equal(t, expected, getVersionInfo())
 	}
 }
+
+func TestBatchSizeFlags(t *testing.T) {
+	blockDest := filepath.Join(t.TempDir(), "block")
+	oldArgs := os.Args
+	defer func() {
+		os.Args = oldArgs
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	}()
+	os.Args = []string{
+		"cmd",
+		"-channelID=testchannelid",
+		"-profile=" + configtxgen.SampleAppChannelSmartBftProfile,
+		"-outputBlock=" + blockDest,
+		"-autoCapabilities",
+		"-batchMaxMessageCount=42",
+		"-batchAbsoluteMaxBytes=8 MB",
+		"-batchPreferredMaxBytes=1024",
+	}
+	configtest.SetDevFabricConfigPath(t)
+
+	main()
+
+	block, err := protoutil.ReadBlockFromFile(blockDest)
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromBlock(block, factory.GetDefault())
+	require.NoError(t, err)
+	ordererConfig, ok := bundle.OrdererConfig()
+	require.True(t, ok)
+	batchSize := ordererConfig.BatchSize()
+	require.Equal(t, uint32(42), batchSize.MaxMessageCount)
+	require.Equal(t, uint32(8*1024*1024), batchSize.AbsoluteMaxBytes)
+	require.Equal(t, uint32(1024), batchSize.PreferredMaxBytes)
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -33,12 +34,17 @@ var (
 	gen           = app.Command("generate", "Generate key material")
 	outputDir     = gen.Flag("output", "The output directory in which to place artifacts").Default("crypto-config").String()
 	genConfigFile = gen.Flag("config", "The configuration template to use").File()
+	summaryFile   = gen.Flag("summary", "Write a JSON summary of the generated material to this file").String()
 	showtemplate  = app.Command("showtemplate", "Show the default configuration template")
 
 	versionCmd    = app.Command("version", "Show version information")
 	ext           = app.Command("extend", "Extend existing network")
 	inputDir      = ext.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
 	extConfigFile = ext.Flag("config", "The configuration template to use").File()
+
+	revoke     = app.Command("revoke", "Revoke a node or user, and add the CRL to its organization's MSPs")
+	revokeOrg  = revoke.Arg("org", "The directory of the organization").Required().String()
+	revokeName = revoke.Arg("name", "The common name of the node or user to revoke").Required().String()
 )
 
 func main() {
@@ -50,6 +56,8 @@ func main() {
 		err = generate()
 	case ext.FullCommand():
 		err = extend()
+	case revoke.FullCommand():
+		err = cryptogen.Revoke(*revokeOrg, *revokeName)
 	case showtemplate.FullCommand():
 		fmt.Print(sampleconfig.DefaultCryptoConfig)
 	case versionCmd.FullCommand():
@@ -75,7 +83,21 @@ func generate() error {
 	if err != nil {
 		return err
 	}
-	return cryptogen.Generate(*outputDir, config)
+	if *summaryFile == "" {
+		return cryptogen.Generate(*outputDir, config)
+	}
+	summary, err := cryptogen.GenerateWithSummary(*outputDir, config)
+	if err != nil {
+		return err
+	}
+	summaryJSON, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling summary: %w", err)
+	}
+	if err = os.WriteFile(*summaryFile, summaryJSON, 0o600); err != nil {
+		return fmt.Errorf("error writing summary: %w", err)
+	}
+	return nil
 }
 
 func getConfig() (*cryptogen.Config, error) {
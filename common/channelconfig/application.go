@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-x-common/common/capabilities"
+	"github.com/hyperledger/fabric-x-common/msp"
 )
 
 const (
@@ -32,6 +33,7 @@ type ApplicationProtos struct {
 type ApplicationConfig struct {
 	applicationOrgs map[string]ApplicationOrg
 	protos          *ApplicationProtos
+	mspVersion      msp.MSPVersion
 }
 
 // NewApplicationConfig creates config from an Application config group
@@ -45,6 +47,10 @@ func NewApplicationConfig(appGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler)
 		return nil, errors.Wrap(err, "failed to deserialize values")
 	}
 
+	if mspConfig != nil {
+		ac.mspVersion = mspConfig.version
+	}
+
 	var err error
 	for orgName, orgGroup := range appGroup.Groups {
 		ac.applicationOrgs[orgName], err = NewApplicationOrgConfig(orgName, orgGroup, mspConfig)
@@ -66,6 +72,11 @@ func (ac *ApplicationConfig) Capabilities() ApplicationCapabilities {
 	return capabilities.NewApplicationProvider(ac.protos.Capabilities.Capabilities)
 }
 
+// MSPVersion returns the MSP version whose validation rules apply to the application organizations
+func (ac *ApplicationConfig) MSPVersion() msp.MSPVersion {
+	return ac.mspVersion
+}
+
 // APIPolicyMapper returns a PolicyMapper that maps API names to policies
 func (ac *ApplicationConfig) APIPolicyMapper() PolicyMapper {
 	pm := newAPIsProvider(ac.protos.ACLs.Acls)
@@ -7,15 +7,22 @@ SPDX-License-Identifier: Apache-2.0
 package channelconfig
 
 import (
+	"bytes"
+	"encoding/json"
+	"slices"
+
 	"github.com/hyperledger/fabric-lib-go/bccsp"
 	"github.com/hyperledger/fabric-lib-go/common/flogging"
 	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
 	"github.com/pkg/errors"
+	"go.yaml.in/yaml/v3"
 
 	"github.com/hyperledger/fabric-x-common/common/cauthdsl"
 	"github.com/hyperledger/fabric-x-common/common/configtx"
 	"github.com/hyperledger/fabric-x-common/common/policies"
 	"github.com/hyperledger/fabric-x-common/msp"
+	"github.com/hyperledger/fabric-x-common/protolator"
+	"github.com/hyperledger/fabric-x-common/protolator/protoext/commonext"
 	"github.com/hyperledger/fabric-x-common/protoutil"
 )
 
@@ -77,6 +84,95 @@ func (b *Bundle) ConfigtxValidator() configtx.Validator {
 	return b.configtxManager
 }
 
+// ToYAML renders the channel config group as YAML.
+// The config group is first decoded to JSON via the protolator, so nested messages
+// are rendered in their human-readable form, and then converted to YAML.
+// Map keys are sorted, making the output deterministic for a given config.
+func (b *Bundle) ToYAML() ([]byte, error) {
+	var buf bytes.Buffer
+	channelGroup := &commonext.DynamicChannelGroup{ConfigGroup: b.configtxManager.ConfigProto().ChannelGroup}
+	if err := protolator.DeepMarshalJSON(&buf, channelGroup); err != nil {
+		return nil, errors.Wrap(err, "failed to decode channel group to JSON")
+	}
+
+	decoder := json.NewDecoder(&buf)
+	decoder.UseNumber()
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, errors.Wrap(err, "failed to parse channel group JSON")
+	}
+
+	out, err := yaml.Marshal(convertJSONNumbers(tree))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode channel group to YAML")
+	}
+	return out, nil
+}
+
+// convertJSONNumbers replaces the json.Number values of a decoded JSON tree
+// with integers or floats, so they are encoded as YAML numbers rather than strings.
+func convertJSONNumbers(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = convertJSONNumbers(e)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = convertJSONNumbers(e)
+		}
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if f, err := t.Float64(); err == nil {
+			return f
+		}
+		return t.String()
+	}
+	return v
+}
+
+// OrgMembershipDiff returns the MSP IDs of the orderer and application organizations
+// that are in the new bundle but not in the old one (added), and vice versa (removed).
+// Both lists are sorted. A nil bundle has no organizations.
+func OrgMembershipDiff(oldBundle, newBundle *Bundle) (added, removed []string) {
+	oldIDs := orgMSPIDs(oldBundle)
+	newIDs := orgMSPIDs(newBundle)
+	for id := range newIDs {
+		if _, ok := oldIDs[id]; !ok {
+			added = append(added, id)
+		}
+	}
+	for id := range oldIDs {
+		if _, ok := newIDs[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
+
+// orgMSPIDs returns the set of MSP IDs of the orderer and application organizations of a bundle.
+func orgMSPIDs(b *Bundle) map[string]struct{} {
+	ids := make(map[string]struct{})
+	if b == nil {
+		return ids
+	}
+	if oc, ok := b.OrdererConfig(); ok {
+		for _, org := range oc.Organizations() {
+			ids[org.MSPID()] = struct{}{}
+		}
+	}
+	if ac, ok := b.ApplicationConfig(); ok {
+		for _, org := range ac.Organizations() {
+			ids[org.MSPID()] = struct{}{}
+		}
+	}
+	return ids
+}
+
 // ValidateNew checks if a new bundle's contained configuration is valid to be derived from the current bundle.
 // This allows checks of the nature "Make sure that the consensus type did not change".
 func (b *Bundle) ValidateNew(nb Resources) error {
@@ -190,6 +286,19 @@ func NewBundleFromEnvelope(env *cb.Envelope, bccsp bccsp.BCCSP) (*Bundle, error)
 	return NewBundle(chdr.ChannelId, configEnvelope.Config, bccsp)
 }
 
+// NewBundleFromBlock wraps the NewBundleFromEnvelope function, extracting the
+// config envelope from a config block
+func NewBundleFromBlock(block *cb.Block, bccsp bccsp.BCCSP) (*Bundle, error) {
+	if len(block.GetData().GetData()) == 0 {
+		return nil, errors.New("block has no data")
+	}
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to extract envelope from block")
+	}
+	return NewBundleFromEnvelope(env, bccsp)
+}
+
 // NewBundle creates a new immutable bundle of configuration
 func NewBundle(channelID string, config *cb.Config, bccsp bccsp.BCCSP) (*Bundle, error) {
 	if err := preValidate(config); err != nil {
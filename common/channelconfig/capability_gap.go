@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelconfig
+
+import (
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+)
+
+// CapabilityGapTo reports, for each capability level of the channel config, i.e., the Channel, Orderer,
+// and Application groups, whether the target capability is not enabled at that level.
+// Levels absent from the config are not reported.
+func (b *Bundle) CapabilityGapTo(target string) map[string]bool {
+	gaps := map[string]bool{
+		ChannelGroupKey: lacksCapability(b.channelConfig.protos.Capabilities, target),
+	}
+	if oc := b.channelConfig.ordererConfig; oc != nil {
+		gaps[OrdererGroupKey] = lacksCapability(oc.protos.Capabilities, target)
+	}
+	if ac := b.channelConfig.appConfig; ac != nil {
+		gaps[ApplicationGroupKey] = lacksCapability(ac.protos.Capabilities, target)
+	}
+	return gaps
+}
+
+// AllBundlesAtCapability reports whether the channel capability is enabled in all the bundles, and returns
+// the IDs of the channels whose bundle lacks it, in order. It helps coordinating upgrades across channels.
+func AllBundlesAtCapability(bundles []*Bundle, capability string) (bool, []string) {
+	var lacking []string
+	for _, b := range bundles {
+		if lacksCapability(b.channelConfig.protos.Capabilities, capability) {
+			lacking = append(lacking, b.ConfigtxValidator().ChannelID())
+		}
+	}
+	return len(lacking) == 0, lacking
+}
+
+func lacksCapability(capabilities *cb.Capabilities, target string) bool {
+	_, ok := capabilities.GetCapabilities()[target]
+	return !ok
+}
@@ -39,3 +39,9 @@ func NewConsortiumsConfig(consortiumsGroup *cb.ConfigGroup, mspConfig *MSPConfig
 func (cc *ConsortiumsConfig) Consortiums() map[string]Consortium {
 	return cc.consortiums
 }
+
+// Consortium returns the consortium with the given name, if it exists
+func (cc *ConsortiumsConfig) Consortium(name string) (Consortium, bool) {
+	consortium, ok := cc.consortiums[name]
+	return consortium, ok
+}
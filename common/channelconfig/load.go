@@ -106,6 +106,16 @@ func LoadConfigBlockMaterial(block *common.Block) (*ConfigBlockMaterial, error)
 	}, nil
 }
 
+// IsConfigEnvelope returns true if the envelope is a config transaction, that is, if its channel header is
+// of type CONFIG. It returns an error if the envelope is malformed.
+func IsConfigEnvelope(env *common.Envelope) (bool, error) {
+	chHead, err := protoutil.ChannelHeader(env)
+	if err != nil {
+		return false, errors.Wrap(err, "malformed envelope")
+	}
+	return chHead.Type == int32(common.HeaderType_CONFIG), nil
+}
+
 // newOrdererOrganizationsMaterialsFromBundle reads the organizations' materials from a config block bundle.
 func newOrdererOrganizationsMaterialsFromBundle(bundle *Bundle) ([]*OrdererOrganizationMaterial, error) {
 	ordererCfg, ok := bundle.OrdererConfig()
@@ -13,11 +13,15 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/hyperledger/fabric-lib-go/bccsp/factory"
 	"github.com/hyperledger/fabric-protos-go-apiv2/common"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	commontypes "github.com/hyperledger/fabric-x-common/api/types"
 	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/common/configtx"
+	"github.com/hyperledger/fabric-x-common/common/policydsl"
 	"github.com/hyperledger/fabric-x-common/protoutil"
 	"github.com/hyperledger/fabric-x-common/tools/configtxgen"
 	"github.com/hyperledger/fabric-x-common/tools/cryptogen"
@@ -73,6 +77,84 @@ func TestLoadConfigBlockFromFile(t *testing.T) {
 	}
 }
 
+func TestOrdererConsenters(t *testing.T) {
+	t.Parallel()
+	material := createConfigBlockMaterial(t, 1, 3)
+	oc, ok := material.Bundle.OrdererConfig()
+	require.True(t, ok)
+	require.Equal(t, "arma", oc.ConsensusType())
+
+	consenters := oc.Consenters()
+	require.Len(t, consenters, 3)
+	ids := make([]uint32, len(consenters))
+	for i, c := range consenters {
+		ids[i] = c.Id
+		require.NotEmpty(t, c.Identity)
+	}
+	require.ElementsMatch(t, []uint32{0, 1, 2}, ids)
+}
+
+func TestOrdererOrgMSPIDs(t *testing.T) {
+	t.Parallel()
+	material := createConfigBlockMaterial(t, 1, 2)
+	orderer, ok := material.Bundle.OrdererConfig()
+	require.True(t, ok)
+	oc, ok := orderer.(*channelconfig.OrdererConfig)
+	require.True(t, ok)
+	require.Equal(t, 2, oc.OrgCount())
+	require.Equal(t, []string{"orderer-org-0", "orderer-org-1"}, oc.OrgMSPIDs())
+}
+
+func TestBundleModPolicies(t *testing.T) {
+	t.Parallel()
+	material := createConfigBlockMaterial(t, 1, 1)
+	modPolicies := material.Bundle.ModPolicies()
+	require.Equal(t, channelconfig.AdminsPolicyKey, modPolicies[configtx.GroupPrefix+"/Channel/Application"])
+	require.Equal(t, channelconfig.AdminsPolicyKey, modPolicies[configtx.PolicyPrefix+"/Channel/Application/Admins"])
+	require.Contains(t, modPolicies, configtx.GroupPrefix+"/Channel")
+	require.Contains(t, modPolicies, configtx.ValuePrefix+"/Channel/Orderer/BatchSize")
+	require.Contains(t, modPolicies, configtx.GroupPrefix+"/Channel/Application/peer-org-0")
+
+	// A value and a policy with the same name are both kept.
+	config := proto.CloneOf(material.Bundle.ConfigtxValidator().ConfigProto())
+	appGroup := config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+	require.Contains(t, appGroup.Values, channelconfig.ACLsKey)
+	appGroup.Values[channelconfig.ACLsKey].ModPolicy = "value-policy"
+	appGroup.Policies[channelconfig.ACLsKey] = &common.ConfigPolicy{
+		Policy: &common.Policy{
+			Type:  int32(common.Policy_SIGNATURE),
+			Value: protoutil.MarshalOrPanic(policydsl.SignedByAnyMember([]string{"peer-org-0"})),
+		},
+		ModPolicy: "policy-policy",
+	}
+	bundle, err := channelconfig.NewBundle(material.ChannelID, config, factory.GetDefault())
+	require.NoError(t, err)
+	modPolicies = bundle.ModPolicies()
+	require.Equal(t, "value-policy", modPolicies[configtx.ValuePrefix+"/Channel/Application/ACLs"])
+	require.Equal(t, "policy-policy", modPolicies[configtx.PolicyPrefix+"/Channel/Application/ACLs"])
+}
+
+func TestValidatePolicyReferences(t *testing.T) {
+	t.Parallel()
+	material := createConfigBlockMaterial(t, 2, 2)
+	require.NoError(t, material.Bundle.ValidatePolicyReferences())
+
+	config := proto.CloneOf(material.Bundle.ConfigtxValidator().ConfigProto())
+	appGroup := config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+	appGroup.Policies["Auditors"] = &common.ConfigPolicy{
+		Policy: &common.Policy{
+			Type:  int32(common.Policy_SIGNATURE),
+			Value: protoutil.MarshalOrPanic(policydsl.SignedByAnyMember([]string{"ghost-org", "peer-org-0"})),
+		},
+		ModPolicy: channelconfig.AdminsPolicyKey,
+	}
+	bundle, err := channelconfig.NewBundle(material.ChannelID, config, factory.GetDefault())
+	require.NoError(t, err)
+	err = bundle.ValidatePolicyReferences()
+	require.EqualError(t, err,
+		"policies reference undefined MSPs: /Channel/Application/Auditors references MSP ghost-org")
+}
+
 func TestLoadConfigBlockFromFileEdgeCases(t *testing.T) {
 	t.Parallel()
 
@@ -220,6 +302,37 @@ func TestOrganizationMaterialExtraction(t *testing.T) {
 	}
 }
 
+func TestIsConfigEnvelope(t *testing.T) {
+	t.Parallel()
+	block, err := protoutil.ReadBlockFromFile(createConfigBlockPath(t, "test-channel", 1, 1))
+	require.NoError(t, err)
+	configEnv, err := protoutil.GetEnvelopeFromBlock(block.Data.Data[0])
+	require.NoError(t, err)
+	isConfig, err := channelconfig.IsConfigEnvelope(configEnv)
+	require.NoError(t, err)
+	require.True(t, isConfig)
+
+	endorserEnv := &common.Envelope{Payload: protoutil.MarshalOrPanic(&common.Payload{
+		Header: protoutil.MakePayloadHeader(
+			protoutil.MakeChannelHeader(common.HeaderType_ENDORSER_TRANSACTION, 0, "test-channel", 0),
+			protoutil.MakeSignatureHeader([]byte("creator"), []byte("nonce")),
+		),
+		Data: []byte("transaction"),
+	})}
+	isConfig, err = channelconfig.IsConfigEnvelope(endorserEnv)
+	require.NoError(t, err)
+	require.False(t, isConfig)
+
+	for _, env := range []*common.Envelope{
+		nil,
+		{Payload: []byte("garbage")},
+		{Payload: protoutil.MarshalOrPanic(&common.Payload{})},
+	} {
+		_, err = channelconfig.IsConfigEnvelope(env)
+		require.ErrorContains(t, err, "malformed envelope")
+	}
+}
+
 // Helper methods
 
 func createConfigBlockPath(
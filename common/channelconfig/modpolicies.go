@@ -0,0 +1,36 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelconfig
+
+import (
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+
+	"github.com/hyperledger/fabric-x-common/common/configtx"
+)
+
+// ModPolicies returns the mod_policy of every group, value, and policy of the channel config, keyed by its
+// fully qualified path, as used by the config transaction validator. The path is prefixed by the kind of element,
+// so elements of different kinds with the same name do not collide, e.g., "[Group]  /Channel/Application" or
+// "[Value]  /Channel/Orderer/BatchSize".
+func (b *Bundle) ModPolicies() map[string]string {
+	modPolicies := make(map[string]string)
+	collectModPolicies(modPolicies, "/"+RootGroupKey, b.ConfigtxValidator().ConfigProto().ChannelGroup)
+	return modPolicies
+}
+
+func collectModPolicies(modPolicies map[string]string, groupPath string, group *cb.ConfigGroup) {
+	modPolicies[configtx.GroupPrefix+groupPath] = group.GetModPolicy()
+	for name, value := range group.GetValues() {
+		modPolicies[configtx.ValuePrefix+groupPath+"/"+name] = value.GetModPolicy()
+	}
+	for name, policy := range group.GetPolicies() {
+		modPolicies[configtx.PolicyPrefix+groupPath+"/"+name] = policy.GetModPolicy()
+	}
+	for name, subGroup := range group.GetGroups() {
+		collectModPolicies(modPolicies, groupPath+"/"+name, subGroup)
+	}
+}
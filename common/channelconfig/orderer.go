@@ -9,6 +9,7 @@ package channelconfig
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -140,7 +141,7 @@ func NewOrdererConfig(ordererGroup *cb.ConfigGroup, mspConfig *MSPConfigHandler,
 		}
 	}
 
-	if oc.ConsensusType() == "arma" || oc.ConsensusType() == "BFT" {
+	if oc.isBFT() {
 		if err := oc.validateAllOrgsHaveEndpoints(); err != nil {
 			return nil, err
 		}
@@ -159,6 +160,13 @@ func (oc *OrdererConfig) ConsensusMetadata() []byte {
 	return oc.protos.ConsensusType.Metadata
 }
 
+// ConsensusTypeAndMetadata returns the configured consensus type along with its metadata,
+// both read from the same consensus type config value.
+func (oc *OrdererConfig) ConsensusTypeAndMetadata() (string, []byte) {
+	consensusType := oc.protos.ConsensusType
+	return consensusType.Type, consensusType.Metadata
+}
+
 // ConsensusState return the consensus type state.
 func (oc *OrdererConfig) ConsensusState() ab.ConsensusType_State {
 	return oc.protos.ConsensusType.State
@@ -184,8 +192,32 @@ func (oc *OrdererConfig) Organizations() map[string]OrdererOrg {
 	return oc.orgs
 }
 
+// OrgCount returns the number of orgs in the channel.
+func (oc *OrdererConfig) OrgCount() int {
+	return len(oc.orgs)
+}
+
+// OrgMSPIDs returns the sorted MSP IDs of the orgs in the channel.
+func (oc *OrdererConfig) OrgMSPIDs() []string {
+	mspIDs := make([]string, 0, len(oc.orgs))
+	for _, org := range oc.orgs {
+		mspIDs = append(mspIDs, org.MSPID())
+	}
+	slices.Sort(mspIDs)
+	return mspIDs
+}
+
+// Consenters returns the identities of the consenting ordering nodes, as decoded from the Orderers value.
+// It returns nil if the consensus type is not BFT based.
 func (oc *OrdererConfig) Consenters() []*cb.Consenter {
-	return oc.protos.Orderers.ConsenterMapping
+	if !oc.isBFT() {
+		return nil
+	}
+	return oc.protos.Orderers.GetConsenterMapping()
+}
+
+func (oc *OrdererConfig) isBFT() bool {
+	return oc.ConsensusType() == "arma" || oc.ConsensusType() == "BFT"
 }
 
 // Capabilities returns the capabilities the ordering network has for this channel.
@@ -9,6 +9,7 @@ package channelconfig
 import (
 	"testing"
 
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
 	ab "github.com/hyperledger/fabric-protos-go-apiv2/orderer"
 	"github.com/stretchr/testify/require"
 )
@@ -41,3 +42,11 @@ func TestBatchTimeout(t *testing.T) {
 	oc = &OrdererConfig{protos: &OrdererProtos{BatchTimeout: &ab.BatchTimeout{Timeout: "0s"}}}
 	require.Error(t, oc.validateBatchTimeout(), "Zero batch timeout")
 }
+
+func TestConsentersNotBFT(t *testing.T) {
+	oc := &OrdererConfig{protos: &OrdererProtos{
+		ConsensusType: &ab.ConsensusType{Type: "etcdraft"},
+		Orderers:      &cb.Orderers{ConsenterMapping: []*cb.Consenter{{Id: 1}}},
+	}}
+	require.Nil(t, oc.Consenters())
+}
@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelconfig
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+	mspproto "github.com/hyperledger/fabric-protos-go-apiv2/msp"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger/fabric-x-common/protoutil"
+)
+
+// ValidatePolicyReferences checks that every MSP referenced by the signature policies
+// of the channel config is defined in the bundle. The returned error names all the
+// dangling references, by policy path and MSP ID.
+func (b *Bundle) ValidatePolicyReferences() error {
+	msps, err := b.MSPManager().GetMSPs()
+	if err != nil {
+		return errors.WithMessage(err, "could not get the channel MSPs")
+	}
+
+	var dangling []string
+	err = walkSignaturePolicies("/"+RootGroupKey, b.ConfigtxValidator().ConfigProto().ChannelGroup,
+		func(policyPath string, principal *mspproto.MSPPrincipal) error {
+			mspIDs, err := principalMSPIDs(principal)
+			if err != nil {
+				return errors.WithMessagef(err, "invalid principal in policy %s", policyPath)
+			}
+			for _, mspID := range mspIDs {
+				if _, ok := msps[mspID]; !ok {
+					dangling = append(dangling, fmt.Sprintf("%s references MSP %s", policyPath, mspID))
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+	if len(dangling) > 0 {
+		slices.Sort(dangling)
+		return errors.Errorf("policies reference undefined MSPs: %s", strings.Join(slices.Compact(dangling), "; "))
+	}
+	return nil
+}
+
+// walkSignaturePolicies invokes fn for each principal of each signature policy of the group and its sub-groups.
+func walkSignaturePolicies(
+	groupPath string,
+	group *cb.ConfigGroup,
+	fn func(policyPath string, principal *mspproto.MSPPrincipal) error,
+) error {
+	for _, name := range slices.Sorted(maps.Keys(group.Policies)) {
+		policy := group.Policies[name].GetPolicy()
+		if policy.GetType() != int32(cb.Policy_SIGNATURE) {
+			continue
+		}
+		policyPath := groupPath + "/" + name
+		envelope := &cb.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Value, envelope); err != nil {
+			return errors.Wrapf(err, "could not unmarshal signature policy %s", policyPath)
+		}
+		for _, principal := range envelope.Identities {
+			if err := fn(policyPath, principal); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(group.Groups)) {
+		if err := walkSignaturePolicies(groupPath+"/"+name, group.Groups[name], fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// principalMSPIDs returns the IDs of the MSPs a principal refers to.
+func principalMSPIDs(principal *mspproto.MSPPrincipal) ([]string, error) {
+	switch principal.PrincipalClassification {
+	case mspproto.MSPPrincipal_ROLE:
+		role := &mspproto.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal MSPRole")
+		}
+		return []string{role.MspIdentifier}, nil
+	case mspproto.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mspproto.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal OrganizationUnit")
+		}
+		return []string{ou.MspIdentifier}, nil
+	case mspproto.MSPPrincipal_IDENTITY:
+		id, err := protoutil.UnmarshalIdentity(principal.Principal)
+		if err != nil {
+			return nil, err
+		}
+		return []string{id.MspId}, nil
+	case mspproto.MSPPrincipal_COMBINED:
+		combined := &mspproto.CombinedPrincipal{}
+		if err := proto.Unmarshal(principal.Principal, combined); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal CombinedPrincipal")
+		}
+		var mspIDs []string
+		for _, p := range combined.Principals {
+			ids, err := principalMSPIDs(p)
+			if err != nil {
+				return nil, err
+			}
+			mspIDs = append(mspIDs, ids...)
+		}
+		return mspIDs, nil
+	default:
+		// Anonymity principals do not refer to an MSP.
+		return nil, nil
+	}
+}
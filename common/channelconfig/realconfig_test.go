@@ -7,16 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package channelconfig_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"path/filepath"
 	"testing"
 
 	"github.com/hyperledger/fabric-lib-go/bccsp/sw"
 	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/orderer"
 	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/hyperledger/fabric-x-common/api/types"
 	"github.com/hyperledger/fabric-x-common/common/channelconfig"
 	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+	"github.com/hyperledger/fabric-x-common/msp"
+	"github.com/hyperledger/fabric-x-common/protolator"
+	"github.com/hyperledger/fabric-x-common/protolator/protoext/commonext"
 	"github.com/hyperledger/fabric-x-common/protoutil"
 	"github.com/hyperledger/fabric-x-common/tools/configtxgen"
 )
@@ -34,6 +42,56 @@ func TestWithRealConfigTX(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNewBundleFromBlock(t *testing.T) {
+	t.Parallel()
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+
+	t.Run("Fabric-X genesis block", func(t *testing.T) {
+		t.Parallel()
+		conf := configtxgen.Load(configtxgen.SampleFabricX, configtest.GetDevConfigDir())
+		conf.Orderer.Arma.Path = filepath.Join(configtest.GetDevConfigDir(), "arma_shared_config.pbbin")
+		gb := configtxgen.New(conf).GenesisBlockForChannel("foo")
+
+		bundle, err := channelconfig.NewBundleFromBlock(gb, cryptoProvider)
+		require.NoError(t, err)
+		require.Equal(t, "foo", bundle.ConfigtxValidator().ChannelID())
+		ordererConfig, ok := bundle.OrdererConfig()
+		require.True(t, ok)
+		require.Equal(t, conf.Orderer.OrdererType, ordererConfig.ConsensusType())
+	})
+
+	t.Run("block with no data", func(t *testing.T) {
+		t.Parallel()
+		_, err := channelconfig.NewBundleFromBlock(&common.Block{}, cryptoProvider)
+		require.EqualError(t, err, "block has no data")
+	})
+}
+
+func TestConsortiumOrganizations(t *testing.T) {
+	t.Parallel()
+	conf := configtxgen.Load(configtxgen.SampleSingleMSPSoloProfile, configtest.GetDevConfigDir())
+	gb := configtxgen.New(conf).GenesisBlockForChannel("system")
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromBlock(gb, cryptoProvider)
+	require.NoError(t, err)
+
+	consortiumsConfig, ok := bundle.ConsortiumsConfig()
+	require.True(t, ok)
+	consortiums, ok := consortiumsConfig.(*channelconfig.ConsortiumsConfig)
+	require.True(t, ok)
+	consortium, ok := consortiums.Consortium(configtxgen.SampleConsortiumName)
+	require.True(t, ok)
+	orgs := consortium.Organizations()
+	require.Len(t, orgs, 1)
+	require.Contains(t, orgs, configtxgen.SampleOrgName)
+	require.Equal(t, configtxgen.SampleOrgName, orgs[configtxgen.SampleOrgName].MSPID())
+
+	_, ok = consortiums.Consortium("MissingConsortium")
+	require.False(t, ok)
+}
+
 func TestOrgSpecificOrdererEndpoints(t *testing.T) {
 	t.Parallel()
 	t.Run("could not create arma orderer config with empty organization endpoints", func(t *testing.T) {
@@ -108,3 +166,213 @@ func TestOrgSpecificOrdererEndpoints(t *testing.T) {
 			"global orderer endpoints exist, but are not supported: [globalAddress]")
 	})
 }
+
+func TestBundleToYAML(t *testing.T) {
+	t.Parallel()
+	conf := configtxgen.Load(configtxgen.SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+	gb := configtxgen.New(conf).GenesisBlockForChannel("foo")
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromBlock(gb, cryptoProvider)
+	require.NoError(t, err)
+
+	out, err := bundle.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, string(out), "SampleOrg")
+
+	// The output is deterministic.
+	again, err := bundle.ToYAML()
+	require.NoError(t, err)
+	require.Equal(t, out, again)
+
+	// The YAML round-trips back to an equivalent config group.
+	var tree any
+	require.NoError(t, yaml.Unmarshal(out, &tree))
+	rawJSON, err := json.Marshal(tree)
+	require.NoError(t, err)
+	group := &commonext.DynamicChannelGroup{ConfigGroup: &common.ConfigGroup{}}
+	require.NoError(t, protolator.DeepUnmarshalJSON(bytes.NewReader(rawJSON), group))
+
+	expected := bundle.ConfigtxValidator().ConfigProto().ChannelGroup
+	roundTrip, err := channelconfig.NewChannelConfig(group.ConfigGroup, cryptoProvider)
+	require.NoError(t, err)
+	require.Equal(t, bundle.ChannelConfig().OrdererAddresses(), roundTrip.OrdererAddresses())
+	require.True(t, proto.Equal(expected, group.ConfigGroup))
+}
+
+func TestConsensusTypeAndMetadata(t *testing.T) {
+	t.Parallel()
+	for _, profile := range []string{configtxgen.SampleAppChannelSmartBftProfile, configtxgen.SampleFabricX} {
+		t.Run(profile, func(t *testing.T) {
+			t.Parallel()
+			conf := configtxgen.Load(profile, configtest.GetDevConfigDir())
+			if conf.Orderer.Arma != nil {
+				conf.Orderer.Arma.Path = filepath.Join(configtest.GetDevConfigDir(), "arma_shared_config.pbbin")
+			}
+			cg, err := configtxgen.NewChannelGroup(conf)
+			require.NoError(t, err)
+
+			cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+			require.NoError(t, err)
+			cc, err := channelconfig.NewChannelConfig(cg, cryptoProvider)
+			require.NoError(t, err)
+
+			expected := &orderer.ConsensusType{}
+			value := cg.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey]
+			require.NoError(t, proto.Unmarshal(value.Value, expected))
+			require.Equal(t, conf.Orderer.OrdererType, expected.Type)
+			require.NotEmpty(t, expected.Metadata)
+
+			consensusType, metadata := cc.OrdererConfig().ConsensusTypeAndMetadata()
+			require.Equal(t, expected.Type, consensusType)
+			require.Equal(t, expected.Metadata, metadata)
+			require.Equal(t, cc.OrdererConfig().ConsensusType(), consensusType)
+			require.Equal(t, cc.OrdererConfig().ConsensusMetadata(), metadata)
+		})
+	}
+}
+
+func TestApplicationMSPVersion(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		name         string
+		capabilities map[string]bool
+		expected     msp.MSPVersion
+	}{
+		{name: "sample capabilities", expected: msp.MSPv1_0},
+		{name: "V3_0", capabilities: map[string]bool{"V3_0": true}, expected: msp.MSPv3_0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			conf := configtxgen.Load(configtxgen.SampleFabricX, configtest.GetDevConfigDir())
+			conf.Orderer.Arma.Path = filepath.Join(configtest.GetDevConfigDir(), "arma_shared_config.pbbin")
+			if tc.capabilities != nil {
+				conf.Capabilities = tc.capabilities
+			}
+			cg, err := configtxgen.NewChannelGroup(conf)
+			require.NoError(t, err)
+
+			cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+			require.NoError(t, err)
+			cc, err := channelconfig.NewChannelConfig(cg, cryptoProvider)
+			require.NoError(t, err)
+
+			ac := cc.ApplicationConfig()
+			require.NotNil(t, ac)
+			require.Equal(t, tc.expected, ac.MSPVersion())
+			require.Equal(t, cc.Capabilities().MSPVersion(), ac.MSPVersion())
+		})
+	}
+}
+
+func TestOrgMembershipDiff(t *testing.T) {
+	t.Parallel()
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	newBundle := func(t *testing.T, conf *configtxgen.Profile) *channelconfig.Bundle {
+		t.Helper()
+		cg, err := configtxgen.NewChannelGroup(conf)
+		require.NoError(t, err)
+		b, err := channelconfig.NewBundle("foo", &common.Config{ChannelGroup: cg}, cryptoProvider)
+		require.NoError(t, err)
+		return b
+	}
+	loadConf := func() *configtxgen.Profile {
+		conf := configtxgen.Load(configtxgen.TwoOrgsSampleFabricX, configtest.GetDevConfigDir())
+		conf.Orderer.Arma.Path = filepath.Join(configtest.GetDevConfigDir(), "arma_shared_config.pbbin")
+		return conf
+	}
+
+	twoOrgs := newBundle(t, loadConf())
+	oneOrgConf := loadConf()
+	removedID := oneOrgConf.Application.Organizations[1].ID
+	oneOrgConf.Application.Organizations = oneOrgConf.Application.Organizations[:1]
+	oneOrgConf.Orderer.Organizations = oneOrgConf.Orderer.Organizations[:1]
+	oneOrg := newBundle(t, oneOrgConf)
+
+	added, removed := channelconfig.OrgMembershipDiff(twoOrgs, oneOrg)
+	require.Empty(t, added)
+	require.Equal(t, []string{removedID}, removed)
+
+	added, removed = channelconfig.OrgMembershipDiff(oneOrg, twoOrgs)
+	require.Equal(t, []string{removedID}, added)
+	require.Empty(t, removed)
+
+	added, removed = channelconfig.OrgMembershipDiff(twoOrgs, twoOrgs)
+	require.Empty(t, added)
+	require.Empty(t, removed)
+}
+
+func TestCapabilityGapTo(t *testing.T) {
+	t.Parallel()
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	newBundle := func(t *testing.T, conf *configtxgen.Profile) *channelconfig.Bundle {
+		t.Helper()
+		cg, err := configtxgen.NewChannelGroup(conf)
+		require.NoError(t, err)
+		b, err := channelconfig.NewBundle("foo", &common.Config{ChannelGroup: cg}, cryptoProvider)
+		require.NoError(t, err)
+		return b
+	}
+
+	conf := configtxgen.Load(configtxgen.SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+	conf.Consortiums = nil
+	conf.Capabilities = map[string]bool{"V2_0": true}
+	conf.Orderer.Capabilities = map[string]bool{"V2_0": true}
+	conf.Application.Capabilities = map[string]bool{"V2_0": true}
+	v2Bundle := newBundle(t, conf)
+	require.Equal(t, map[string]bool{
+		channelconfig.ChannelGroupKey:     true,
+		channelconfig.OrdererGroupKey:     true,
+		channelconfig.ApplicationGroupKey: true,
+	}, v2Bundle.CapabilityGapTo("V3_0"))
+	require.Equal(t, map[string]bool{
+		channelconfig.ChannelGroupKey:     false,
+		channelconfig.OrdererGroupKey:     false,
+		channelconfig.ApplicationGroupKey: false,
+	}, v2Bundle.CapabilityGapTo("V2_0"))
+
+	conf.Capabilities = map[string]bool{"V3_0": true}
+	conf.Application = nil
+	v3Bundle := newBundle(t, conf)
+	require.Equal(t, map[string]bool{
+		channelconfig.ChannelGroupKey: false,
+		channelconfig.OrdererGroupKey: true,
+	}, v3Bundle.CapabilityGapTo("V3_0"))
+}
+
+func TestAllBundlesAtCapability(t *testing.T) {
+	t.Parallel()
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	newBundle := func(t *testing.T, channelID, capability string) *channelconfig.Bundle {
+		t.Helper()
+		conf := configtxgen.Load(configtxgen.SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		conf.Consortiums = nil
+		conf.Capabilities = map[string]bool{capability: true}
+		conf.Orderer.Capabilities = map[string]bool{"V2_0": true}
+		cg, err := configtxgen.NewChannelGroup(conf)
+		require.NoError(t, err)
+		b, err := channelconfig.NewBundle(channelID, &common.Config{ChannelGroup: cg}, cryptoProvider)
+		require.NoError(t, err)
+		return b
+	}
+
+	bundles := []*channelconfig.Bundle{
+		newBundle(t, "upgraded", "V3_0"),
+		newBundle(t, "legacy-1", "V2_0"),
+		newBundle(t, "legacy-2", "V2_0"),
+	}
+	ready, lacking := channelconfig.AllBundlesAtCapability(bundles, "V3_0")
+	require.False(t, ready)
+	require.Equal(t, []string{"legacy-1", "legacy-2"}, lacking)
+
+	ready, lacking = channelconfig.AllBundlesAtCapability(bundles[:1], "V3_0")
+	require.True(t, ready)
+	require.Empty(t, lacking)
+
+	ready, lacking = channelconfig.AllBundlesAtCapability(nil, "V3_0")
+	require.True(t, ready)
+	require.Empty(t, lacking)
+}
@@ -16,10 +16,12 @@ import (
 	"github.com/hyperledger/fabric-x-common/protoutil"
 )
 
+// The prefixes of the fully qualified paths of config groups, values, and policies, which make the paths of
+// elements of different kinds unambiguous, e.g., "[Value]  /Channel/Orderer/BatchSize".
 const (
-	groupPrefix  = "[Group]  "
-	valuePrefix  = "[Value]  "
-	policyPrefix = "[Policy] "
+	GroupPrefix  = "[Group]  "
+	ValuePrefix  = "[Value]  "
+	PolicyPrefix = "[Policy] "
 
 	pathSeparator = "/"
 
@@ -47,11 +49,11 @@ func addToMap(cg comparable, result map[string]comparable) error {
 
 	switch {
 	case cg.ConfigGroup != nil:
-		fqPath = groupPrefix
+		fqPath = GroupPrefix
 	case cg.ConfigValue != nil:
-		fqPath = valuePrefix
+		fqPath = ValuePrefix
 	case cg.ConfigPolicy != nil:
-		fqPath = policyPrefix
+		fqPath = PolicyPrefix
 	}
 
 	if err := validateConfigID(cg.key); err != nil {
@@ -111,7 +113,7 @@ func configMapToConfig(configMap map[string]comparable, rootGroupKey string) (*c
 // recurseConfigMap is used only internally by configMapToConfig
 // Note, this function no longer mutates the cb.Config* entries within configMap
 func recurseConfigMap(path string, configMap map[string]comparable) (*cb.ConfigGroup, error) {
-	groupPath := groupPrefix + path
+	groupPath := GroupPrefix + path
 	group, ok := configMap[groupPath]
 	if !ok {
 		return nil, errors.Errorf("missing group at path: %s", groupPath)
@@ -133,7 +135,7 @@ func recurseConfigMap(path string, configMap map[string]comparable) (*cb.ConfigG
 	}
 
 	for key := range group.Values {
-		valuePath := valuePrefix + path + pathSeparator + key
+		valuePath := ValuePrefix + path + pathSeparator + key
 		value, ok := configMap[valuePath]
 		if !ok {
 			return nil, errors.Errorf("missing value at path: %s", valuePath)
@@ -145,7 +147,7 @@ func recurseConfigMap(path string, configMap map[string]comparable) (*cb.ConfigG
 	}
 
 	for key := range group.Policies {
-		policyPath := policyPrefix + path + pathSeparator + key
+		policyPath := PolicyPrefix + path + pathSeparator + key
 		policy, ok := configMap[policyPath]
 		if !ok {
 			return nil, errors.Errorf("missing policy at path: %s", policyPath)
@@ -173,14 +175,14 @@ func recurseConfigMap(path string, configMap map[string]comparable) (*cb.ConfigG
 
 		for key, value := range newConfigGroup.Values {
 			if value.ModPolicy == "" {
-				logger.Debugf("Performing upgrade of value %s empty mod_policy", valuePrefix+path+pathSeparator+key)
+				logger.Debugf("Performing upgrade of value %s empty mod_policy", ValuePrefix+path+pathSeparator+key)
 				value.ModPolicy = hackyFixNewModPolicy
 			}
 		}
 
 		for key, policy := range newConfigGroup.Policies {
 			if policy.ModPolicy == "" {
-				logger.Debugf("Performing upgrade of policy %s empty mod_policy", policyPrefix+path+pathSeparator+key)
+				logger.Debugf("Performing upgrade of policy %s empty mod_policy", PolicyPrefix+path+pathSeparator+key)
 
 				policy.ModPolicy = hackyFixNewModPolicy
 			}
@@ -8,9 +8,13 @@ package blocksprovider
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/fabric-protos-go-apiv2/orderer"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor
 
 	"github.com/hyperledger/fabric-x-common/tools/pkg/comm"
 )
@@ -25,8 +29,44 @@ func (da DialerAdapter) Dial(address string, rootCerts [][]byte) (*grpc.ClientCo
 	return cc.Dial(address)
 }
 
-type DeliverAdapter struct{}
+// DialWithTimeout dials like Dial, but bounded by the given timeout instead of the configured one.
+func (da DialerAdapter) DialWithTimeout(address string, rootCerts [][]byte, timeout time.Duration) (*grpc.ClientConn, error) {
+	cc := da.ClientConfig
+	cc.SecOpts.ServerRootCAs = rootCerts
+	cc.DialTimeout = timeout
+	return cc.Dial(address)
+}
+
+type DeliverAdapter struct {
+	callOptions []grpc.CallOption
+}
+
+// DeliverOption configures the deliver streams created by a DeliverAdapter.
+type DeliverOption func(*DeliverAdapter) error
+
+// WithCompression requests the blocks of the deliver stream to be compressed with the named
+// compressor (e.g., "gzip"). The compressor must be registered with gRPC.
+func WithCompression(name string) DeliverOption {
+	return func(da *DeliverAdapter) error {
+		if encoding.GetCompressor(name) == nil {
+			return errors.Errorf("compressor '%s' is not registered", name)
+		}
+		da.callOptions = append(da.callOptions, grpc.UseCompressor(name))
+		return nil
+	}
+}
+
+// NewDeliverAdapter creates a DeliverAdapter with the given options.
+func NewDeliverAdapter(opts ...DeliverOption) (DeliverAdapter, error) {
+	da := DeliverAdapter{}
+	for _, opt := range opts {
+		if err := opt(&da); err != nil {
+			return DeliverAdapter{}, err
+		}
+	}
+	return da, nil
+}
 
-func (DeliverAdapter) Deliver(ctx context.Context, clientConn *grpc.ClientConn) (orderer.AtomicBroadcast_DeliverClient, error) {
-	return orderer.NewAtomicBroadcastClient(clientConn).Deliver(ctx)
+func (da DeliverAdapter) Deliver(ctx context.Context, clientConn *grpc.ClientConn) (orderer.AtomicBroadcast_DeliverClient, error) {
+	return orderer.NewAtomicBroadcastClient(clientConn).Deliver(ctx, da.callOptions...)
 }
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blocksprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestDeliverAdapterWithCompression(t *testing.T) {
+	t.Parallel()
+
+	errIntercepted := errors.New("intercepted")
+	var callOptions []grpc.CallOption
+	conn, err := grpc.NewClient("passthrough:///orderer",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStreamInterceptor(func(
+			_ context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, _ string, _ grpc.Streamer,
+			opts ...grpc.CallOption,
+		) (grpc.ClientStream, error) {
+			callOptions = opts
+			return nil, errIntercepted
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	da, err := NewDeliverAdapter()
+	require.NoError(t, err)
+	_, err = da.Deliver(context.Background(), conn)
+	require.ErrorIs(t, err, errIntercepted)
+	require.NotContains(t, callOptions, grpc.UseCompressor("gzip"))
+
+	da, err = NewDeliverAdapter(WithCompression("gzip"))
+	require.NoError(t, err)
+	_, err = da.Deliver(context.Background(), conn)
+	require.ErrorIs(t, err, errIntercepted)
+	require.Contains(t, callOptions, grpc.UseCompressor("gzip"))
+
+	_, err = NewDeliverAdapter(WithCompression("no-such-compressor"))
+	require.EqualError(t, err, "compressor 'no-such-compressor' is not registered")
+}
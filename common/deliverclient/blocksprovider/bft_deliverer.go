@@ -368,11 +368,15 @@ func (d *BFTDeliverer) FetchBlocks(source *orderers.Endpoint) {
 		d.blockReceiver = blockRcv
 		d.mutex.Unlock()
 
+		acquireEndpoint(d.orderers, source)
 		// Starts a goroutine that receives blocks from the stream client and places them in the `recvC` channel
 		blockRcv.Start()
 
 		// Consume blocks from the `recvC` channel
-		if errProc := blockRcv.ProcessIncoming(d.onBlockProcessingSuccess); errProc != nil {
+		errProc := blockRcv.ProcessIncoming(d.onBlockProcessingSuccess)
+		// The connection is closed, so the endpoint is released, and removed if it is draining.
+		releaseEndpoint(d.orderers, source)
+		if errProc != nil {
 			switch errProc.(type) {
 			case *ErrStopping:
 				// nothing to do
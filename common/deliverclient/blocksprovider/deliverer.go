@@ -58,6 +58,12 @@ type Dialer interface {
 	Dial(address string, rootCerts [][]byte) (*grpc.ClientConn, error)
 }
 
+// TimeoutDialer is a Dialer that can bound a single dial with a timeout.
+// When the dialer implements it, endpoints with a dial timeout are dialed with their own timeout.
+type TimeoutDialer interface {
+	DialWithTimeout(address string, rootCerts [][]byte, timeout time.Duration) (*grpc.ClientConn, error)
+}
+
 //go:generate counterfeiter -o fake/deliver_streamer.go --fake-name DeliverStreamer . DeliverStreamer
 type DeliverStreamer interface {
 	Deliver(context.Context, *grpc.ClientConn) (orderer.AtomicBroadcast_DeliverClient, error)
@@ -186,6 +192,7 @@ func (d *Deliverer) DeliverBlocks() {
 		deliverClient, cancel, err := d.requester.Connect(seekInfoEnv, endpoint)
 		if err != nil {
 			d.Logger.Warningf("Could not connect to ordering service: %s", err)
+			d.markFailed(endpoint)
 			failureCounter++
 			continue
 		}
@@ -205,6 +212,7 @@ func (d *Deliverer) DeliverBlocks() {
 		d.blockReceiver = blockReceiver
 		d.mutex.Unlock()
 
+		acquireEndpoint(d.orderers, endpoint)
 		blockReceiver.Start() // starts an internal goroutine
 		onSuccess := func(blockNum uint64, channelConfig *cb.Config) {
 			failureCounter = 0
@@ -219,7 +227,10 @@ func (d *Deliverer) DeliverBlocks() {
 				d.orderers.Update(globalAddresses, orgAddresses)
 			}
 		}
-		if err := blockReceiver.ProcessIncoming(onSuccess); err != nil {
+		err = blockReceiver.ProcessIncoming(onSuccess)
+		// The connection is closed, so the endpoint is released, and removed if it is draining.
+		releaseEndpoint(d.orderers, endpoint)
+		if err != nil {
 			switch err.(type) {
 			case *errRefreshEndpoint:
 				// Don't count it as an error, we'll reconnect immediately.
@@ -227,12 +238,20 @@ func (d *Deliverer) DeliverBlocks() {
 				// Don't count it as an error, it is a signal to stop.
 			default:
 				d.Logger.Warningf("Failure in processing incoming messages: %s", err)
+				d.markFailed(endpoint)
 				failureCounter++
 			}
 		}
 	}
 }
 
+// markFailed reports a failed endpoint to the connection source, if it takes failures into account.
+func (d *Deliverer) markFailed(endpoint *orderers.Endpoint) {
+	if reporter, ok := d.orderers.(orderers.EndpointFailureReporter); ok {
+		reporter.MarkFailed(endpoint)
+	}
+}
+
 // Stop stops blocks delivery provider
 func (d *Deliverer) Stop() {
 	d.mutex.Lock()
@@ -243,6 +243,40 @@ var _ = ginkgo.Describe("CFT-Deliverer", func() {
 		})
 	})
 
+	ginkgo.When("the orderer connection source drains removed endpoints", func() {
+		var cs *orderers.ConnectionSource
+
+		ginkgo.BeforeEach(func() {
+			factory := &orderers.ConnectionSourceFactory{Draining: true}
+			var ok bool
+			cs, ok = factory.CreateConnectionSource(flogging.MustGetLogger("test"), "").(*orderers.ConnectionSource)
+			gomega.Expect(ok).To(gomega.BeTrue())
+			fakeOrdererConnectionSourceFactory.CreateConnectionSourceReturns(cs)
+			d.Initialize(channelConfig)
+			// The connection is closed by the orderer, so closing the sending side does not wait for it.
+			fakeDeliverClient.CloseSendStub = nil
+		})
+
+		ginkgo.It("keeps the connection to a removed endpoint, and releases the endpoint once it is closed", func() {
+			gomega.Eventually(fakeDeliverClient.RecvCallCount, eventuallyTO).Should(gomega.BeNumerically(">", 0))
+
+			cs.Update([]string{"other-address:7050"}, nil)
+			draining := cs.DrainingEndpoints()
+			gomega.Expect(draining).To(gomega.HaveLen(1))
+			addr, _ := fakeDialer.DialArgsForCall(0)
+			gomega.Expect(draining[0].Address).To(gomega.Equal(addr))
+			gomega.Consistently(draining[0].Refreshed).ShouldNot(gomega.BeClosed())
+			gomega.Expect(fakeDialer.DialCallCount()).To(gomega.Equal(1))
+
+			gomega.Eventually(recvStep, eventuallyTO).Should(gomega.BeSent(struct{}{}))
+			gomega.Eventually(cs.DrainingEndpoints, eventuallyTO).Should(gomega.BeEmpty())
+			gomega.Expect(draining[0].Refreshed).To(gomega.BeClosed())
+			gomega.Eventually(fakeDialer.DialCallCount, eventuallyTO).Should(gomega.Equal(2))
+			addr, _ = fakeDialer.DialArgsForCall(1)
+			gomega.Expect(addr).To(gomega.Equal("other-address:7050"))
+		})
+	})
+
 	ginkgo.It("dials the random endpoint", func() {
 		gomega.Eventually(fakeDialer.DialCallCount, eventuallyTO).Should(gomega.Equal(1))
 		addr, tlsCerts := fakeDialer.DialArgsForCall(0)
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go-apiv2/common"
 	"github.com/hyperledger/fabric-protos-go-apiv2/orderer"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
 
 	"github.com/hyperledger/fabric-x-common/common/deliverclient/orderers"
 	"github.com/hyperledger/fabric-x-common/protoutil"
@@ -101,7 +102,7 @@ func (dr *DeliveryRequester) SeekInfoNewestHeader() (*common.Envelope, error) {
 }
 
 func (dr *DeliveryRequester) Connect(seekInfoEnv *common.Envelope, endpoint *orderers.Endpoint) (orderer.AtomicBroadcast_DeliverClient, func(), error) {
-	conn, err := dr.dialer.Dial(endpoint.Address, endpoint.RootCerts)
+	conn, err := dr.dial(endpoint)
 	if err != nil {
 		return nil, nil, errors.WithMessagef(err, "could not dial endpoint '%s'", endpoint.Address)
 	}
@@ -131,3 +132,11 @@ func (dr *DeliveryRequester) Connect(seekInfoEnv *common.Envelope, endpoint *ord
 
 	return deliverClient, cancelFunc, nil
 }
+
+// dial dials the endpoint, bounded by its dial timeout if it has one and the dialer supports it.
+func (dr *DeliveryRequester) dial(endpoint *orderers.Endpoint) (*grpc.ClientConn, error) {
+	if td, ok := dr.dialer.(TimeoutDialer); ok && endpoint.DialTimeout > 0 {
+		return td.DialWithTimeout(endpoint.Address, endpoint.RootCerts, endpoint.DialTimeout)
+	}
+	return dr.dialer.Dial(endpoint.Address, endpoint.RootCerts)
+}
@@ -7,8 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package blocksprovider_test
 
 import (
+	"net"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/hyperledger/fabric-lib-go/common/flogging"
 	"github.com/hyperledger/fabric-protos-go-apiv2/common"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -21,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric-x-common/common/deliverclient/blocksprovider/fake"
 	"github.com/hyperledger/fabric-x-common/common/deliverclient/orderers"
 	"github.com/hyperledger/fabric-x-common/protoutil/identity/mocks"
+	"github.com/hyperledger/fabric-x-common/tools/pkg/comm"
 )
 
 func TestDeliveryRequester_Connect_Success(t *testing.T) {
@@ -198,3 +203,66 @@ func TestDeliveryRequester_SeekInfoHeadersFrom(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, envelope)
 }
+
+func TestDeliveryRequester_Connect_DialTimeout(t *testing.T) {
+	// A listener that accepts connections but never completes the handshake, like a hung orderer.
+	stalledListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = stalledListener.Close() })
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				_ = conn.Close()
+			}
+		}()
+		for {
+			conn, acceptErr := stalledListener.Accept()
+			if acceptErr != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	healthyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	go func() { _ = server.Serve(healthyListener) }()
+	t.Cleanup(server.Stop)
+
+	stalledAddress := stalledListener.Addr().String()
+	healthyAddress := healthyListener.Addr().String()
+	cs := orderers.NewConnectionSource(flogging.MustGetLogger("test"), nil, "")
+	cs.SetDialTimeouts(0, map[string]time.Duration{stalledAddress: 200 * time.Millisecond})
+	cs.Update(nil, map[string]orderers.OrdererOrg{
+		"org1": {Addresses: []string{stalledAddress, healthyAddress}},
+	})
+	// Try the stalled endpoint first.
+	endpoints := slices.Clone(cs.Endpoints())
+	if endpoints[0].Address != stalledAddress {
+		endpoints[0], endpoints[1] = endpoints[1], endpoints[0]
+	}
+
+	fakeSigner := &mocks.SignerSerializer{}
+	fakeDeliverStreamer := &fake.DeliverStreamer{}
+	fakeDeliverStreamer.DeliverReturns(&fake.DeliverClient{}, nil)
+	dialer := blocksprovider.DialerAdapter{ClientConfig: comm.ClientConfig{DialTimeout: time.Minute}}
+	dr := blocksprovider.NewDeliveryRequester("channel-id", fakeSigner, nil, dialer, fakeDeliverStreamer)
+
+	var connected *orderers.Endpoint
+	for _, endpoint := range endpoints {
+		start := time.Now()
+		_, cancelFunc, connectErr := dr.Connect(&common.Envelope{}, endpoint)
+		if connectErr != nil {
+			require.Equal(t, stalledAddress, endpoint.Address)
+			require.Less(t, time.Since(start), 5*time.Second, "dial was not aborted by the endpoint's timeout")
+			continue
+		}
+		cancelFunc()
+		connected = endpoint
+		break
+	}
+	require.NotNil(t, connected)
+	require.Equal(t, healthyAddress, connected.Address)
+}
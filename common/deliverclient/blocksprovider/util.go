@@ -112,3 +112,17 @@ func extractAddresses(channelID string, config *cb.Config, cryptoProvider bccsp.
 
 	return globalAddresses, orgAddresses, nil
 }
+
+// acquireEndpoint reports an open connection to the connection source, if it tracks connections.
+func acquireEndpoint(source OrdererConnectionSource, endpoint *orderers.Endpoint) {
+	if tracker, ok := source.(orderers.ConnectionTracker); ok {
+		tracker.Acquire(endpoint)
+	}
+}
+
+// releaseEndpoint reports a closed connection to the connection source, if it tracks connections.
+func releaseEndpoint(source OrdererConnectionSource, endpoint *orderers.Endpoint) {
+	if tracker, ok := source.(orderers.ConnectionTracker); ok {
+		tracker.Release(endpoint)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-x-common/tools/fileutil"
+)
+
+// ErrNoCheckpoint is returned by Checkpoint.LoadCheckpoint when no progress was saved yet.
+var ErrNoCheckpoint = errors.New("no checkpoint")
+
+// Checkpoint persists the progress of Tail, so that a consumer resumes where it stopped after a restart.
+type Checkpoint interface {
+	// LoadCheckpoint returns the number of the next block to process,
+	// or ErrNoCheckpoint if no progress was saved yet.
+	LoadCheckpoint() (uint64, error)
+	// SaveCheckpoint saves the number of the next block to process.
+	SaveCheckpoint(next uint64) error
+}
+
+// FileCheckpoint is a Checkpoint that keeps the block number in a file.
+// The file is replaced atomically on each save, so a crash never leaves a partial checkpoint.
+type FileCheckpoint struct {
+	Path string
+}
+
+// LoadCheckpoint reads the block number from the file.
+func (c *FileCheckpoint) LoadCheckpoint() (uint64, error) {
+	content, err := os.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return 0, ErrNoCheckpoint
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "could not read checkpoint %s", c.Path)
+	}
+	next, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "malformed checkpoint %s", c.Path)
+	}
+	return next, nil
+}
+
+// SaveCheckpoint writes the block number to the file.
+func (c *FileCheckpoint) SaveCheckpoint(next uint64) error {
+	dir, name := filepath.Split(c.Path)
+	tmpName := name + ".tmp"
+	// A crash between creating and renaming the temporary file leaves it behind.
+	if err := os.Remove(filepath.Join(dir, tmpName)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "could not remove stale checkpoint %s", tmpName)
+	}
+	content := []byte(strconv.FormatUint(next, 10))
+	return fileutil.CreateAndSyncFileAtomically(dir, tmpName, name, content, 0o600)
+}
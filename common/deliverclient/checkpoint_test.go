@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/common/deliverclient"
+)
+
+func TestFileCheckpoint(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "checkpoint")
+	checkpoint := &deliverclient.FileCheckpoint{Path: path}
+
+	_, err := checkpoint.LoadCheckpoint()
+	require.ErrorIs(t, err, deliverclient.ErrNoCheckpoint)
+
+	require.NoError(t, checkpoint.SaveCheckpoint(42))
+	next, err := checkpoint.LoadCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), next)
+
+	// A temporary file left behind by a crash does not prevent saving.
+	require.NoError(t, os.WriteFile(path+".tmp", []byte("garbage"), 0o600))
+	require.NoError(t, checkpoint.SaveCheckpoint(43))
+	next, err = checkpoint.LoadCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, uint64(43), next)
+
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0o600))
+	_, err = checkpoint.LoadCheckpoint()
+	require.ErrorContains(t, err, "malformed checkpoint "+path)
+}
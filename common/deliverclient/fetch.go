@@ -0,0 +1,147 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/orderer"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-x-common/protoutil"
+	"github.com/hyperledger/fabric-x-common/protoutil/identity"
+)
+
+// FetchLatestConfigBlock fetches the newest block of a channel, and then the config block its
+// LAST_CONFIG metadata points to. It returns that config block.
+func FetchLatestConfigBlock(
+	ctx context.Context,
+	source DeliverStreamSource,
+	channelID string,
+	signer identity.SignerSerializer,
+) (*common.Block, error) {
+	newest, err := fetchBlock(ctx, source, channelID, signer, &orderer.SeekPosition{
+		Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}},
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not fetch the newest block")
+	}
+	lastConfig, err := protoutil.GetLastConfigIndexFromBlock(newest)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not get the last config index of block [%d]",
+			newest.Header.Number)
+	}
+
+	configBlock := newest
+	if lastConfig != newest.Header.Number {
+		configBlock, err = FetchBlock(ctx, source, channelID, signer, lastConfig)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not fetch config block [%d]", lastConfig)
+		}
+	}
+	if _, err = ConfigFromBlock(configBlock); err != nil {
+		return nil, errors.WithMessagef(err, "block [%d] is not a valid config block", configBlock.Header.Number)
+	}
+	return configBlock, nil
+}
+
+// FetchOption configures FetchBlock.
+type FetchOption func(*fetchConfig)
+
+type fetchConfig struct {
+	deadline time.Duration
+}
+
+// WithFetchDeadline bounds the time FetchBlock waits for the block, e.g., a block that is not yet committed.
+// When the block is not delivered in time, FetchBlock returns a *FetchTimeoutError.
+func WithFetchDeadline(deadline time.Duration) FetchOption {
+	return func(c *fetchConfig) {
+		c.deadline = deadline
+	}
+}
+
+// FetchTimeoutError is returned by FetchBlock when the block is not delivered before the fetch deadline.
+// Failures of the connection or of the deliver stream are reported with other errors.
+type FetchTimeoutError struct {
+	Number   uint64
+	Deadline time.Duration
+}
+
+func (e *FetchTimeoutError) Error() string {
+	return fmt.Sprintf("block [%d] was not delivered within %s", e.Number, e.Deadline)
+}
+
+// FetchBlock fetches the block with the given number of a channel.
+// Without a deadline, it waits until the block is delivered or ctx is done.
+func FetchBlock( //nolint:revive // argument-limit; max 4 but got 6
+	ctx context.Context,
+	source DeliverStreamSource,
+	channelID string,
+	signer identity.SignerSerializer,
+	number uint64,
+	opts ...FetchOption,
+) (*common.Block, error) {
+	conf := &fetchConfig{}
+	for _, opt := range opts {
+		opt(conf)
+	}
+	fetchCtx := ctx
+	if conf.deadline > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(ctx, conf.deadline)
+		defer cancel()
+	}
+
+	block, err := fetchBlock(fetchCtx, source, channelID, signer, &orderer.SeekPosition{
+		Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: number}},
+	})
+	if err != nil {
+		// Only the expiry of the fetch deadline is a timeout. The expiry of ctx is reported as is.
+		if ctx.Err() == nil && errors.Is(fetchCtx.Err(), context.DeadlineExceeded) {
+			return nil, &FetchTimeoutError{Number: number, Deadline: conf.deadline}
+		}
+		return nil, err
+	}
+	if block.Header.Number != number {
+		return nil, errors.Errorf("expected block [%d] but got block [%d]", number, block.Header.Number)
+	}
+	return block, nil
+}
+
+// fetchBlock opens a deliver stream for the single block at the given position, and returns that block.
+func fetchBlock( //nolint:revive // argument-limit; max 4 but got 5
+	ctx context.Context,
+	source DeliverStreamSource,
+	channelID string,
+	signer identity.SignerSerializer,
+	position *orderer.SeekPosition,
+) (*common.Block, error) {
+	seekEnv, err := protoutil.CreateSignedEnvelope(
+		common.HeaderType_DELIVER_SEEK_INFO, channelID, signer, &orderer.SeekInfo{
+			Start:    position,
+			Stop:     position,
+			Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+		}, int32(0), uint64(0),
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not create seek info envelope")
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := source.OpenDeliverStream(streamCtx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not open deliver stream")
+	}
+	if err = stream.Send(seekEnv); err != nil {
+		return nil, errors.WithMessage(err, "could not send seek info")
+	}
+	return receiveBlock(stream)
+}
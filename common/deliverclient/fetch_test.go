@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient_test
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/orderer"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger/fabric-x-common/common/deliverclient"
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+	"github.com/hyperledger/fabric-x-common/protoutil"
+	"github.com/hyperledger/fabric-x-common/protoutil/identity/mocks"
+	"github.com/hyperledger/fabric-x-common/tools/configtxgen"
+)
+
+func TestFetchLatestConfigBlock(t *testing.T) {
+	t.Parallel()
+	conf := configtxgen.Load(configtxgen.SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+	genesis := configtxgen.New(conf).GenesisBlockForChannel("mychannel")
+
+	t.Run("newest block points to an older config block", func(t *testing.T) {
+		t.Parallel()
+		server := &fetchServer{blocks: []*common.Block{genesis, dataBlock(1, 0), dataBlock(2, 0)}}
+		source := startDeliverServer(t, server)
+
+		block, err := deliverclient.FetchLatestConfigBlock(t.Context(), source, "mychannel", &mocks.SignerSerializer{})
+		require.NoError(t, err)
+		require.True(t, proto.Equal(genesis, block))
+		require.Equal(t, []string{"newest", "0"}, server.seeks())
+	})
+
+	t.Run("newest block is the config block", func(t *testing.T) {
+		t.Parallel()
+		server := &fetchServer{blocks: []*common.Block{genesis}}
+		source := startDeliverServer(t, server)
+
+		block, err := deliverclient.FetchLatestConfigBlock(t.Context(), source, "mychannel", &mocks.SignerSerializer{})
+		require.NoError(t, err)
+		require.True(t, proto.Equal(genesis, block))
+		require.Equal(t, []string{"newest"}, server.seeks())
+	})
+
+	t.Run("last config points to a data block", func(t *testing.T) {
+		t.Parallel()
+		server := &fetchServer{blocks: []*common.Block{genesis, dataBlock(1, 0), dataBlock(2, 1)}}
+		source := startDeliverServer(t, server)
+
+		_, err := deliverclient.FetchLatestConfigBlock(t.Context(), source, "mychannel", &mocks.SignerSerializer{})
+		require.ErrorContains(t, err, "block [1] is not a valid config block")
+	})
+}
+
+func TestFetchBlock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delivered before the deadline", func(t *testing.T) {
+		t.Parallel()
+		server := &fetchServer{blocks: []*common.Block{dataBlock(0, 0), dataBlock(1, 0)}}
+		source := startDeliverServer(t, server)
+
+		block, err := deliverclient.FetchBlock(t.Context(), source, "mychannel", &mocks.SignerSerializer{}, 1,
+			deliverclient.WithFetchDeadline(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), block.Header.Number)
+	})
+
+	t.Run("not delivered before the deadline", func(t *testing.T) {
+		t.Parallel()
+		source := startDeliverServer(t, &silentServer{})
+
+		start := time.Now()
+		_, err := deliverclient.FetchBlock(t.Context(), source, "mychannel", &mocks.SignerSerializer{}, 5,
+			deliverclient.WithFetchDeadline(100*time.Millisecond))
+		require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+		var timeoutErr *deliverclient.FetchTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		require.Equal(t, uint64(5), timeoutErr.Number)
+		require.EqualError(t, err, "block [5] was not delivered within 100ms")
+	})
+
+	t.Run("connection failure", func(t *testing.T) {
+		t.Parallel()
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		require.NoError(t, lis.Close())
+		conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = conn.Close()
+		})
+
+		_, err = deliverclient.FetchBlock(t.Context(), &deliverclient.ConnStreamSource{Conn: conn}, "mychannel",
+			&mocks.SignerSerializer{}, 5, deliverclient.WithFetchDeadline(time.Minute))
+		require.Error(t, err)
+		var timeoutErr *deliverclient.FetchTimeoutError
+		require.NotErrorAs(t, err, &timeoutErr)
+	})
+}
+
+// dataBlock returns a block with a non-config transaction, whose metadata points to the given last config block.
+func dataBlock(number, lastConfig uint64) *common.Block {
+	block := protoutil.NewBlock(number, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(&common.Envelope{
+		Payload: protoutil.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{
+				Type: int32(common.HeaderType_ENDORSER_TRANSACTION),
+			})},
+		}),
+	})}
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&common.Metadata{
+		Value: protoutil.MarshalOrPanic(&common.OrdererBlockMetadata{
+			LastConfig: &common.LastConfig{Index: lastConfig},
+		}),
+	})
+	return block
+}
+
+// fetchServer delivers the single block requested by the seek position, either the newest or a specified one.
+type fetchServer struct {
+	orderer.UnimplementedAtomicBroadcastServer
+	blocks []*common.Block
+
+	lock      sync.Mutex
+	positions []string
+}
+
+func (s *fetchServer) Deliver(stream orderer.AtomicBroadcast_DeliverServer) error {
+	env, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	seekInfo := &orderer.SeekInfo{}
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return err
+	}
+
+	block := s.blocks[len(s.blocks)-1]
+	position := "newest"
+	if specified := seekInfo.Start.GetSpecified(); specified != nil {
+		block = s.blocks[specified.Number]
+		position = strconv.FormatUint(specified.Number, 10)
+	}
+	s.lock.Lock()
+	s.positions = append(s.positions, position)
+	s.lock.Unlock()
+
+	return stream.Send(&orderer.DeliverResponse{Type: &orderer.DeliverResponse_Block{Block: block}})
+}
+
+func (s *fetchServer) seeks() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.positions
+}
+
+// silentServer accepts deliver requests, but never delivers a block.
+type silentServer struct {
+	orderer.UnimplementedAtomicBroadcastServer
+}
+
+func (*silentServer) Deliver(stream orderer.AtomicBroadcast_DeliverServer) error {
+	<-stream.Context().Done()
+	return stream.Context().Err()
+}
@@ -11,11 +11,16 @@ import (
 	"crypto/md5"
 	"crypto/sha256"
 	"fmt"
+	"maps"
 	"math/rand"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric-lib-go/common/flogging"
 	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-x-common/api/types"
 )
 
 type ConnectionSource struct {
@@ -23,14 +28,28 @@ type ConnectionSource struct {
 	allEndpoints       []*Endpoint       // All endpoints, excluding the self-endpoint.
 	orgToEndpointsHash map[string][]byte // Used to detect whether the endpoints or certificates has changed.
 	logger             *flogging.FabricLogger
-	overrides          map[string]*Endpoint // In the peer, it is used to override an orderer endpoint.
-	selfEndpoint       string               // Empty when used by a peer, or the self-endpoint when used by an orderer.
+	overrides          map[string]*Endpoint     // In the peer, it is used to override an orderer endpoint.
+	selfEndpoint       string                   // Empty when used by a peer, or the self-endpoint when used by an orderer.
+	dialTimeout        time.Duration            // The dial timeout of endpoints with no specific dial timeout.
+	dialTimeouts       map[string]time.Duration // Dial timeouts by endpoint address.
+	sticky             bool                     // When set, RandomEndpoint sticks to one endpoint until it fails.
+	stickyEndpoint     *Endpoint                // The current sticky endpoint, nil when none was chosen yet.
+	failedEndpoint     *Endpoint                // The last sticky endpoint that failed, avoided by the next choice.
+	latencies          map[string]time.Duration // Probed latencies of the healthy endpoints, by address.
+	unhealthy          map[string]struct{}      // Addresses of the endpoints whose last probe failed.
+	draining           bool                     // When set, endpoints removed by an update are drained.
+	drainingEndpoints  map[string]*Endpoint     // Removed endpoints whose connections are not released yet.
+	connections        map[*Endpoint]int        // Number of open connections by endpoint, see Acquire.
 }
 
 type Endpoint struct {
 	Address   string
 	RootCerts [][]byte
 	Refreshed chan struct{}
+	// DialTimeout bounds dialing the endpoint, so a dead endpoint fails fast. Zero means the dialer's default.
+	DialTimeout time.Duration
+	// Tags are the metadata of the endpoint's orderer org, e.g., region=eu, used to route to specific endpoints.
+	Tags map[string]string
 }
 
 func (e *Endpoint) String() string {
@@ -55,29 +74,168 @@ func (e *Endpoint) String() string {
 type OrdererOrg struct {
 	Addresses []string
 	RootCerts [][]byte
+	// Tags are arbitrary key-value metadata, attached to the endpoints of the org.
+	Tags map[string]string
 }
 
 func (o *OrdererOrg) String() string {
 	return fmt.Sprintf("Addresses: %v", o.Addresses)
 }
 
+// NewConnectionSource creates a connection source. The addresses of the overrides and the self-endpoint are matched
+// against the orderer addresses once normalized, so equivalent IP addresses match (see types.NormalizeAddress).
 func NewConnectionSource(logger *flogging.FabricLogger, overrides map[string]*Endpoint, selfEndpoint string) *ConnectionSource {
 	return &ConnectionSource{
 		orgToEndpointsHash: map[string][]byte{},
 		logger:             logger,
-		overrides:          overrides,
-		selfEndpoint:       selfEndpoint,
+		overrides:          normalizeKeys(overrides),
+		selfEndpoint:       types.NormalizeAddress(selfEndpoint),
+	}
+}
+
+// SetDialTimeouts sets the dial timeout of the endpoints by address, and a default dial timeout for all other
+// endpoints. A zero timeout leaves the dialer's default in place. The timeouts apply to the endpoints prepared by
+// subsequent updates.
+func (cs *ConnectionSource) SetDialTimeouts(defaultTimeout time.Duration, perEndpoint map[string]time.Duration) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.dialTimeout = defaultTimeout
+	cs.dialTimeouts = normalizeKeys(perEndpoint)
+}
+
+// SetSticky enables or disables endpoint affinity. When enabled, RandomEndpoint keeps returning the same
+// endpoint until it is marked as failed, or until the endpoints are updated.
+func (cs *ConnectionSource) SetSticky(sticky bool) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.sticky = sticky
+	cs.stickyEndpoint = nil
+	cs.failedEndpoint = nil
+}
+
+// SetDraining enables or disables graceful draining. When enabled, an endpoint removed by an update while it has
+// open connections (see Acquire) is not refreshed, so these connections may complete, but it is no longer selected.
+// It is fully removed once its connections are released with Release. Disabling draining refreshes the endpoints
+// that are still draining.
+func (cs *ConnectionSource) SetDraining(draining bool) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.draining = draining
+	if draining {
+		return
+	}
+	for address, endpoint := range cs.drainingEndpoints {
+		close(endpoint.Refreshed)
+		delete(cs.drainingEndpoints, address)
+	}
+}
+
+// DrainingEndpoints returns the endpoints that were removed by an update, but are still draining.
+func (cs *ConnectionSource) DrainingEndpoints() []*Endpoint {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+
+	endpoints := make([]*Endpoint, 0, len(cs.drainingEndpoints))
+	for _, address := range slices.Sorted(maps.Keys(cs.drainingEndpoints)) {
+		endpoints = append(endpoints, cs.drainingEndpoints[address])
+	}
+	return endpoints
+}
+
+// Acquire reports that a connection to the endpoint was opened. Each call must be matched by a call to Release
+// once the connection is closed.
+func (cs *ConnectionSource) Acquire(endpoint *Endpoint) {
+	if endpoint == nil {
+		return
+	}
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if cs.connections == nil {
+		cs.connections = map[*Endpoint]int{}
 	}
+	cs.connections[endpoint]++
 }
 
-// RandomEndpoint returns a random endpoint.
+// Release reports that a connection to the endpoint was closed. If the endpoint is draining and this was its
+// last open connection, it is fully removed, and its refreshed channel is closed.
+func (cs *ConnectionSource) Release(endpoint *Endpoint) {
+	if endpoint == nil {
+		return
+	}
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if cs.connections[endpoint] > 1 {
+		cs.connections[endpoint]--
+		return
+	}
+	delete(cs.connections, endpoint)
+	if cs.drainingEndpoints[endpoint.Address] != endpoint {
+		return
+	}
+	cs.logger.Debugf("Draining endpoint [%s] was released", endpoint.Address)
+	close(endpoint.Refreshed)
+	delete(cs.drainingEndpoints, endpoint.Address)
+}
+
+// RandomEndpoint returns a random endpoint, or the sticky endpoint when endpoint affinity is enabled.
+// When latencies are probed, the endpoint is chosen among the fastest healthy ones.
 func (cs *ConnectionSource) RandomEndpoint() (*Endpoint, error) {
+	if cs.isSticky() {
+		return cs.StickyEndpoint()
+	}
+
 	cs.mutex.RLock()
 	defer cs.mutex.RUnlock()
 	if len(cs.allEndpoints) == 0 {
 		return nil, errors.Errorf("no endpoints currently defined")
 	}
-	return cs.allEndpoints[rand.Intn(len(cs.allEndpoints))], nil
+	candidates := cs.preferFastest(cs.allEndpoints)
+	return candidates[rand.Intn(len(candidates))], nil
+}
+
+// StickyEndpoint returns the current sticky endpoint. If there is none, because none was chosen yet, the last one
+// failed, or the endpoints were updated, a random endpoint is chosen and becomes the sticky one. The endpoint that
+// failed last is not chosen again, unless it is the only one.
+func (cs *ConnectionSource) StickyEndpoint() (*Endpoint, error) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if cs.stickyEndpoint != nil {
+		return cs.stickyEndpoint, nil
+	}
+	if len(cs.allEndpoints) == 0 {
+		return nil, errors.Errorf("no endpoints currently defined")
+	}
+
+	candidates := cs.allEndpoints
+	if len(candidates) > 1 && cs.failedEndpoint != nil {
+		candidates = make([]*Endpoint, 0, len(cs.allEndpoints))
+		for _, endpoint := range cs.allEndpoints {
+			if endpoint != cs.failedEndpoint {
+				candidates = append(candidates, endpoint)
+			}
+		}
+	}
+	candidates = cs.preferFastest(candidates)
+	cs.stickyEndpoint = candidates[rand.Intn(len(candidates))]
+	return cs.stickyEndpoint, nil
+}
+
+func (cs *ConnectionSource) isSticky() bool {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+	return cs.sticky
+}
+
+// MarkFailed reports that connecting to, or streaming from, the endpoint failed.
+// If it is the sticky endpoint, the next selection chooses another one.
+func (cs *ConnectionSource) MarkFailed(endpoint *Endpoint) {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if endpoint != nil && endpoint == cs.stickyEndpoint {
+		cs.logger.Debugf("Sticky endpoint [%s] failed, a new one will be selected", endpoint.Address)
+		cs.stickyEndpoint = nil
+		cs.failedEndpoint = endpoint
+	}
 }
 
 func (cs *ConnectionSource) Endpoints() []*Endpoint {
@@ -87,6 +245,20 @@ func (cs *ConnectionSource) Endpoints() []*Endpoint {
 	return cs.allEndpoints
 }
 
+// EndpointsByTag returns the endpoints whose tag key has the given value.
+func (cs *ConnectionSource) EndpointsByTag(key, value string) []*Endpoint {
+	cs.mutex.RLock()
+	defer cs.mutex.RUnlock()
+
+	var endpoints []*Endpoint
+	for _, endpoint := range cs.allEndpoints {
+		if tag, ok := endpoint.Tags[key]; ok && tag == value {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
 // ShuffledEndpoints returns a shuffled array of endpoints in a new slice.
 func (cs *ConnectionSource) ShuffledEndpoints() []*Endpoint {
 	cs.mutex.RLock()
@@ -125,6 +297,9 @@ func (cs *ConnectionSource) Update(globalAddrs []string, orgs map[string]Orderer
 			hasOrgEndpoints = true
 			hasher.Write([]byte(address))
 		}
+		for _, key := range slices.Sorted(maps.Keys(org.Tags)) {
+			hasher.Write([]byte(key + "=" + org.Tags[key] + "\x00"))
+		}
 		hash := hasher.Sum(nil)
 
 		newOrgToEndpointsHash[orgName] = hash
@@ -164,7 +339,7 @@ func (cs *ConnectionSource) Update(globalAddrs []string, orgs map[string]Orderer
 
 		newAddresses := map[string]struct{}{}
 		for _, address := range globalAddrs {
-			newAddresses[address] = struct{}{}
+			newAddresses[types.NormalizeAddress(address)] = struct{}{}
 		}
 
 		for _, endpoint := range cs.allEndpoints {
@@ -187,18 +362,13 @@ func (cs *ConnectionSource) Update(globalAddrs []string, orgs map[string]Orderer
 		return
 	}
 
-	for _, endpoint := range cs.allEndpoints {
-		// Alert any existing consumers that have a reference to the old endpoints
-		// that their reference is now stale and they should get a new one.
-		// This is done even for endpoints which have the same TLS certs and address
-		// but this is desirable to help load balance.  For instance if only
-		// one orderer were defined, and the config is updated to include 4 more, we
-		// want the peers to disconnect from that original orderer and reconnect
-		// evenly across the now five.
-		close(endpoint.Refreshed)
-	}
+	// The old endpoints are retired once the new ones are prepared.
+	defer cs.retire(cs.allEndpoints)
 
 	cs.allEndpoints = nil
+	// The sticky endpoint was refreshed, so a new one is chosen from the updated endpoints.
+	cs.stickyEndpoint = nil
+	cs.failedEndpoint = nil
 
 	var globalRootCerts [][]byte
 
@@ -214,24 +384,25 @@ func (cs *ConnectionSource) Update(globalAddrs []string, orgs map[string]Orderer
 
 		// Note, if !hasOrgEndpoints, this for loop is a no-op
 		for _, address := range org.Addresses {
+			address = types.NormalizeAddress(address)
 			if address == cs.selfEndpoint {
 				cs.logger.Debugf("Skipping self endpoint [%s] from org specific endpoints", address)
 				continue
 			}
 			overrideEndpoint, ok := cs.overrides[address]
 			if ok {
-				cs.allEndpoints = append(cs.allEndpoints, &Endpoint{
-					Address:   overrideEndpoint.Address,
-					RootCerts: overrideEndpoint.RootCerts,
-					Refreshed: make(chan struct{}),
-				})
+				endpoint := cs.overriddenEndpoint(address, overrideEndpoint)
+				endpoint.Tags = org.Tags
+				cs.allEndpoints = append(cs.allEndpoints, endpoint)
 				continue
 			}
 
 			cs.allEndpoints = append(cs.allEndpoints, &Endpoint{
-				Address:   address,
-				RootCerts: rootCerts,
-				Refreshed: make(chan struct{}),
+				Address:     address,
+				RootCerts:   rootCerts,
+				Refreshed:   make(chan struct{}),
+				DialTimeout: cs.dialTimeoutFor(address),
+				Tags:        org.Tags,
 			})
 		}
 	}
@@ -244,26 +415,95 @@ func (cs *ConnectionSource) Update(globalAddrs []string, orgs map[string]Orderer
 	}
 
 	for _, address := range globalAddrs {
+		address = types.NormalizeAddress(address)
 		if address == cs.selfEndpoint {
 			cs.logger.Debugf("Skipping self endpoint [%s] from global endpoints", address)
 			continue
 		}
 		overrideEndpoint, ok := cs.overrides[address]
 		if ok {
-			cs.allEndpoints = append(cs.allEndpoints, &Endpoint{
-				Address:   overrideEndpoint.Address,
-				RootCerts: overrideEndpoint.RootCerts,
-				Refreshed: make(chan struct{}),
-			})
+			cs.allEndpoints = append(cs.allEndpoints, cs.overriddenEndpoint(address, overrideEndpoint))
 			continue
 		}
 
 		cs.allEndpoints = append(cs.allEndpoints, &Endpoint{
-			Address:   address,
-			RootCerts: globalRootCerts,
-			Refreshed: make(chan struct{}),
+			Address:     address,
+			RootCerts:   globalRootCerts,
+			Refreshed:   make(chan struct{}),
+			DialTimeout: cs.dialTimeoutFor(address),
 		})
 	}
 
 	cs.logger.Debug("Returning an orderer connection pool source with global endpoints only")
 }
+
+// retire alerts the consumers of the old endpoints that were replaced by an update. When draining is enabled, the
+// endpoints that were removed while they have open connections are drained rather than refreshed.
+// It must be called with the mutex held.
+func (cs *ConnectionSource) retire(oldEndpoints []*Endpoint) {
+	current := make(map[string]struct{}, len(cs.allEndpoints))
+	for _, endpoint := range cs.allEndpoints {
+		current[endpoint.Address] = struct{}{}
+	}
+	// A draining endpoint that was added back is refreshed, so its consumers use the new endpoint.
+	for address, endpoint := range cs.drainingEndpoints {
+		if _, ok := current[address]; ok {
+			close(endpoint.Refreshed)
+			delete(cs.drainingEndpoints, address)
+		}
+	}
+
+	for _, endpoint := range oldEndpoints {
+		if _, ok := current[endpoint.Address]; cs.draining && !ok && cs.connections[endpoint] > 0 {
+			cs.logger.Debugf("Draining removed endpoint [%s]", endpoint.Address)
+			if cs.drainingEndpoints == nil {
+				cs.drainingEndpoints = map[string]*Endpoint{}
+			}
+			cs.drainingEndpoints[endpoint.Address] = endpoint
+			continue
+		}
+		// Alert any existing consumers that have a reference to the old endpoints
+		// that their reference is now stale and they should get a new one.
+		// This is done even for endpoints which have the same TLS certs and address
+		// but this is desirable to help load balance.  For instance if only
+		// one orderer were defined, and the config is updated to include 4 more, we
+		// want the peers to disconnect from that original orderer and reconnect
+		// evenly across the now five.
+		close(endpoint.Refreshed)
+	}
+}
+
+// overriddenEndpoint prepares the endpoint of an address that is overridden by the given endpoint.
+// The dial timeout of the override takes precedence over the one configured for the address.
+func (cs *ConnectionSource) overriddenEndpoint(address string, override *Endpoint) *Endpoint {
+	dialTimeout := override.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = cs.dialTimeoutFor(address)
+	}
+	return &Endpoint{
+		Address:     types.NormalizeAddress(override.Address),
+		RootCerts:   override.RootCerts,
+		Refreshed:   make(chan struct{}),
+		DialTimeout: dialTimeout,
+	}
+}
+
+// dialTimeoutFor returns the dial timeout of the given endpoint address.
+func (cs *ConnectionSource) dialTimeoutFor(address string) time.Duration {
+	if timeout, ok := cs.dialTimeouts[address]; ok {
+		return timeout
+	}
+	return cs.dialTimeout
+}
+
+// normalizeKeys returns a copy of the map keyed by normalized addresses.
+func normalizeKeys[V any](byAddress map[string]V) map[string]V {
+	if byAddress == nil {
+		return nil
+	}
+	normalized := make(map[string]V, len(byAddress))
+	for address, value := range byAddress {
+		normalized[types.NormalizeAddress(address)] = value
+	}
+	return normalized
+}
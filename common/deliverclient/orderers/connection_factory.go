@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package orderers
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric-lib-go/common/flogging"
 )
 
@@ -16,6 +18,19 @@ type ConnectionSourcer interface {
 	Update(globalAddrs []string, orgs map[string]OrdererOrg)
 }
 
+// EndpointFailureReporter is implemented by connection sources that take endpoint failures into account
+// when selecting endpoints.
+type EndpointFailureReporter interface {
+	MarkFailed(endpoint *Endpoint)
+}
+
+// ConnectionTracker is implemented by connection sources that track the open connections to their endpoints,
+// so removed endpoints can be drained until these connections are closed.
+type ConnectionTracker interface {
+	Acquire(endpoint *Endpoint)
+	Release(endpoint *Endpoint)
+}
+
 type ConnectionSourceCreator interface {
 	// CreateConnectionSource creates a ConnectionSourcer implementation.
 	// In a peer, selfEndpoint == "";
@@ -25,8 +40,20 @@ type ConnectionSourceCreator interface {
 
 type ConnectionSourceFactory struct {
 	Overrides map[string]*Endpoint
+	// DialTimeout is the dial timeout of endpoints not listed in EndpointDialTimeouts.
+	DialTimeout time.Duration
+	// EndpointDialTimeouts holds dial timeouts by endpoint address.
+	EndpointDialTimeouts map[string]time.Duration
+	// Sticky makes the created connection sources stick to an endpoint until it fails.
+	Sticky bool
+	// Draining makes the created connection sources drain removed endpoints until their connections are closed.
+	Draining bool
 }
 
 func (f *ConnectionSourceFactory) CreateConnectionSource(logger *flogging.FabricLogger, selfEndpoint string) ConnectionSourcer {
-	return NewConnectionSource(logger, f.Overrides, selfEndpoint)
+	cs := NewConnectionSource(logger, f.Overrides, selfEndpoint)
+	cs.SetDialTimeouts(f.DialTimeout, f.EndpointDialTimeouts)
+	cs.SetSticky(f.Sticky)
+	cs.SetDraining(f.Draining)
+	return cs
 }
@@ -35,3 +35,28 @@ func TestCreateConnectionSource(t *testing.T) {
 	connSource = factory.CreateConnectionSource(lg, "")
 	require.NotNil(t, connSource)
 }
+
+func TestCreateConnectionSourceDraining(t *testing.T) {
+	t.Parallel()
+	factory := &orderers.ConnectionSourceFactory{Draining: true}
+	connSource := factory.CreateConnectionSource(flogging.MustGetLogger("test"), "")
+	cs, ok := connSource.(*orderers.ConnectionSource)
+	require.True(t, ok)
+
+	cs.Update([]string{"orderer1:7050", "orderer2:7050"}, nil)
+	endpoints := cs.Endpoints()
+	require.Len(t, endpoints, 2)
+	cs.Acquire(endpoints[0])
+
+	cs.Update([]string{"orderer3:7050"}, nil)
+	require.Equal(t, endpoints[:1], cs.DrainingEndpoints())
+	select {
+	case <-endpoints[0].Refreshed:
+		t.Fatal("the draining endpoint was refreshed")
+	default:
+	}
+
+	cs.Release(endpoints[0])
+	require.Empty(t, cs.DrainingEndpoints())
+	<-endpoints[0].Refreshed
+}
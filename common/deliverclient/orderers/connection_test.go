@@ -8,9 +8,12 @@ package orderers_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/hyperledger/fabric-lib-go/common/flogging"
 	. "github.com/onsi/ginkgo/v2"
@@ -40,6 +43,19 @@ func stripEndpoints(endpoints []*orderers.Endpoint) []comparableEndpoint {
 	return endpointsWithChannelStripped
 }
 
+// fakeProber reports fixed latencies by endpoint address, and fails for the other endpoints.
+type fakeProber struct {
+	latencies map[string]time.Duration
+}
+
+func (p *fakeProber) Probe(_ context.Context, endpoint *orderers.Endpoint) (time.Duration, error) {
+	latency, ok := p.latencies[endpoint.Address]
+	if !ok {
+		return 0, errors.New("unreachable")
+	}
+	return latency, nil
+}
+
 var _ = Describe("Connection", func() {
 	var (
 		cert1 []byte
@@ -301,6 +317,252 @@ var _ = Describe("Connection", func() {
 		})
 	})
 
+	When("dial timeouts are configured", func() {
+		BeforeEach(func() {
+			cs.SetDialTimeouts(time.Second, map[string]time.Duration{
+				"org1-address1":    100 * time.Millisecond,
+				"override-address": 200 * time.Millisecond,
+			})
+			cs.Update(nil, map[string]orderers.OrdererOrg{
+				"org1": {
+					Addresses: []string{"org1-address1", "org1-address2", "override-address"},
+					RootCerts: [][]byte{cert1, cert2},
+				},
+			})
+		})
+
+		It("sets the dial timeout of each endpoint", func() {
+			dialTimeouts := map[string]time.Duration{}
+			for _, endpoint := range cs.Endpoints() {
+				dialTimeouts[endpoint.Address] = endpoint.DialTimeout
+			}
+			Expect(dialTimeouts).To(Equal(map[string]time.Duration{
+				"org1-address1":     100 * time.Millisecond,
+				"org1-address2":     time.Second,
+				"re-mapped-address": 200 * time.Millisecond,
+			}))
+		})
+	})
+
+	When("the addresses are IPv6 literals", func() {
+		BeforeEach(func() {
+			cs = orderers.NewConnectionSource(flogging.MustGetLogger("peer.orderers"),
+				map[string]*orderers.Endpoint{
+					"[0:0::3]:7050": {
+						Address:   "[0:0::30]:7050",
+						RootCerts: overrideCerts,
+					},
+				},
+				"[0:0::1]:7050") //<< self-endpoint
+			cs.SetDialTimeouts(time.Second, map[string]time.Duration{
+				"[0:0::2]:7050": 100 * time.Millisecond,
+			})
+			cs.Update(nil, map[string]orderers.OrdererOrg{
+				"org1": {
+					Addresses: []string{"[::1]:7050", "[::2]:7050", "[::3]:7050", "[2001:DB8::1]:7050"},
+					RootCerts: [][]byte{cert1, cert2},
+				},
+			})
+		})
+
+		It("matches the overrides, dial timeouts, and self-endpoint by the normalized addresses", func() {
+			dialTimeouts := map[string]time.Duration{}
+			for _, endpoint := range cs.Endpoints() {
+				dialTimeouts[endpoint.Address] = endpoint.DialTimeout
+			}
+			Expect(dialTimeouts).To(Equal(map[string]time.Duration{
+				"[::2]:7050":         100 * time.Millisecond,
+				"[::30]:7050":        time.Second,
+				"[2001:db8::1]:7050": time.Second,
+			}))
+		})
+
+		It("does not update the endpoints when the global addresses are equivalent", func() {
+			cs = orderers.NewConnectionSource(flogging.MustGetLogger("peer.orderers"), nil, "")
+			cs.Update([]string{"[::1]:7050", "[::2]:7050"}, nil)
+			endpoints = cs.Endpoints()
+			cs.Update([]string{"[0:0::1]:7050", "[0:0::2]:7050"}, nil)
+			Expect(cs.Endpoints()).To(Equal(endpoints))
+			for _, endpoint := range endpoints {
+				Expect(endpoint.Refreshed).NotTo(BeClosed())
+			}
+		})
+	})
+
+	When("endpoint affinity is enabled", func() {
+		BeforeEach(func() {
+			cs.SetSticky(true)
+		})
+
+		It("returns the same endpoint until it is marked as failed", func() {
+			sticky, err := cs.StickyEndpoint()
+			Expect(err).NotTo(HaveOccurred())
+			for range 20 {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoint).To(BeIdenticalTo(sticky))
+			}
+
+			// Failures of other endpoints do not reset the sticky endpoint.
+			for _, endpoint := range endpoints {
+				if endpoint != sticky {
+					cs.MarkFailed(endpoint)
+				}
+			}
+			endpoint, err := cs.RandomEndpoint()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(endpoint).To(BeIdenticalTo(sticky))
+
+			cs.MarkFailed(sticky)
+			newSticky, err := cs.StickyEndpoint()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newSticky).NotTo(BeIdenticalTo(sticky))
+			for range 20 {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoint).To(BeIdenticalTo(newSticky))
+			}
+		})
+
+		It("chooses a new endpoint when the endpoints are updated", func() {
+			sticky, err := cs.StickyEndpoint()
+			Expect(err).NotTo(HaveOccurred())
+			cs.Update(nil, map[string]orderers.OrdererOrg{
+				"org1": org1,
+			})
+			Expect(sticky.Refreshed).To(BeClosed())
+
+			newSticky, err := cs.RandomEndpoint()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cs.Endpoints()).To(ContainElement(BeIdenticalTo(newSticky)))
+		})
+	})
+
+	When("orderer orgs are tagged", func() {
+		BeforeEach(func() {
+			org1.Tags = map[string]string{"region": "eu"}
+			org2.Tags = map[string]string{"region": "us"}
+			cs.Update(nil, map[string]orderers.OrdererOrg{
+				"org1": org1,
+				"org2": org2,
+			})
+		})
+
+		It("refreshes the endpoints", func() {
+			for _, endpoint := range endpoints {
+				Expect(endpoint.Refreshed).To(BeClosed())
+			}
+		})
+
+		It("selects the endpoints by tag", func() {
+			eu := cs.EndpointsByTag("region", "eu")
+			Expect(stripEndpoints(eu)).To(ConsistOf(
+				stripEndpoints([]*orderers.Endpoint{
+					{Address: "org1-address1", RootCerts: org1Certs},
+					{Address: "org1-address2", RootCerts: org1Certs},
+				}),
+			))
+			for _, endpoint := range eu {
+				Expect(endpoint.Tags).To(Equal(map[string]string{"region": "eu"}))
+			}
+			Expect(cs.EndpointsByTag("region", "ap")).To(BeEmpty())
+			Expect(cs.EndpointsByTag("zone", "eu")).To(BeEmpty())
+		})
+	})
+
+	When("latency probing is enabled", func() {
+		var prober *fakeProber
+
+		BeforeEach(func() {
+			prober = &fakeProber{
+				latencies: map[string]time.Duration{
+					"org1-address1": 50 * time.Millisecond,
+					"org1-address2": 40 * time.Millisecond,
+					"org2-address1": time.Millisecond,
+				},
+			}
+		})
+
+		It("prefers the endpoint with the lowest latency", func() {
+			cs.ProbeLatencies(context.Background(), prober)
+			for range 20 {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoint.Address).To(Equal("org2-address1"))
+			}
+		})
+
+		It("prefers the endpoint with the lowest latency when affinity is enabled", func() {
+			cs.SetSticky(true)
+			cs.ProbeLatencies(context.Background(), prober)
+			endpoint, err := cs.RandomEndpoint()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(endpoint.Address).To(Equal("org2-address1"))
+
+			// The failed endpoint is avoided, so the next fastest healthy endpoint is chosen.
+			cs.MarkFailed(endpoint)
+			endpoint, err = cs.RandomEndpoint()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(endpoint.Address).To(Equal("org1-address2"))
+		})
+
+		// There is a chance of failure here, but it is very small.
+		It("selects the endpoints added by an update before they are probed", func() {
+			cs.ProbeLatencies(context.Background(), prober)
+			cs.Update(nil, map[string]orderers.OrdererOrg{
+				"org1": org1,
+				"org3": {Addresses: []string{"org3-address1"}, RootCerts: org2Certs},
+			})
+
+			// The fastest probed endpoint was removed. The new endpoint is not probed yet,
+			// so it is a candidate along with the fastest remaining endpoint.
+			selected := map[string]struct{}{}
+			for range 100 {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				selected[endpoint.Address] = struct{}{}
+			}
+			Expect(selected).To(Equal(map[string]struct{}{"org1-address2": {}, "org3-address1": {}}))
+
+			// Once probed, the new endpoint is preferred only if it is fast enough.
+			prober.latencies["org3-address1"] = time.Second
+			cs.ProbeLatencies(context.Background(), prober)
+			for range 20 {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoint.Address).To(Equal("org1-address2"))
+			}
+		})
+
+		It("does not select the endpoints whose probe failed", func() {
+			delete(prober.latencies, "org2-address1")
+			cs.ProbeLatencies(context.Background(), prober)
+			for range 50 {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoint.Address).To(Equal("org1-address2"))
+			}
+		})
+
+		It("chooses among all the endpoints once probing stops", func() {
+			stop := cs.StartLatencyProbing(prober, time.Millisecond)
+			Eventually(func() string {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				return endpoint.Address
+			}).Should(Equal("org2-address1"))
+			stop()
+
+			selected := map[string]struct{}{}
+			Eventually(func() int {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				selected[endpoint.Address] = struct{}{}
+				return len(selected)
+			}).Should(Equal(len(endpoints)))
+		})
+	})
+
 	When("an update removes an ordering organization", func() {
 		BeforeEach(func() {
 			cs.Update(nil, map[string]orderers.OrdererOrg{
@@ -364,6 +626,109 @@ var _ = Describe("Connection", func() {
 		})
 	})
 
+	When("draining is enabled and an update removes an ordering organization", func() {
+		BeforeEach(func() {
+			cs.SetDraining(true)
+			for _, endpoint := range endpoints {
+				if strings.HasPrefix(endpoint.Address, "org1-") {
+					cs.Acquire(endpoint)
+				}
+			}
+			cs.Update(nil, map[string]orderers.OrdererOrg{
+				"org2": org2,
+			})
+		})
+
+		It("no longer selects the removed endpoints", func() {
+			Expect(stripEndpoints(cs.Endpoints())).To(ConsistOf(
+				stripEndpoints([]*orderers.Endpoint{
+					{Address: "org2-address1", RootCerts: org2Certs},
+					{Address: "org2-address2", RootCerts: org2Certs},
+				}),
+			))
+			for range 100 {
+				endpoint, err := cs.RandomEndpoint()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(endpoint.Address).To(HavePrefix("org2-"))
+			}
+		})
+
+		It("keeps the existing connections to the removed endpoints until they are released", func() {
+			var removed []*orderers.Endpoint
+			for _, endpoint := range endpoints {
+				if strings.HasPrefix(endpoint.Address, "org1-") {
+					removed = append(removed, endpoint)
+					Expect(endpoint.Refreshed).NotTo(BeClosed())
+				} else {
+					Expect(endpoint.Refreshed).To(BeClosed())
+				}
+			}
+			draining := cs.DrainingEndpoints()
+			Expect(draining).To(ConsistOf(removed))
+
+			cs.Release(draining[0])
+			Expect(draining[0].Refreshed).To(BeClosed())
+			Expect(draining[1].Refreshed).NotTo(BeClosed())
+			Expect(cs.DrainingEndpoints()).To(Equal([]*orderers.Endpoint{draining[1]}))
+		})
+
+		It("keeps a draining endpoint until all of its connections are released", func() {
+			draining := cs.DrainingEndpoints()
+			cs.Acquire(draining[0])
+			cs.Release(draining[0])
+			Expect(draining[0].Refreshed).NotTo(BeClosed())
+			Expect(cs.DrainingEndpoints()).To(HaveLen(2))
+
+			cs.Release(draining[0])
+			Expect(draining[0].Refreshed).To(BeClosed())
+			cs.Release(draining[1])
+			Expect(draining[1].Refreshed).To(BeClosed())
+			Expect(cs.DrainingEndpoints()).To(BeEmpty())
+		})
+
+		It("refreshes the draining endpoints when draining is disabled", func() {
+			draining := cs.DrainingEndpoints()
+			cs.SetDraining(false)
+			Expect(cs.DrainingEndpoints()).To(BeEmpty())
+			for _, endpoint := range draining {
+				Expect(endpoint.Refreshed).To(BeClosed())
+			}
+		})
+
+		When("the org is added back", func() {
+			BeforeEach(func() {
+				cs.Update(nil, map[string]orderers.OrdererOrg{
+					"org1": org1,
+					"org2": org2,
+				})
+			})
+
+			It("refreshes the draining endpoints", func() {
+				Expect(cs.DrainingEndpoints()).To(BeEmpty())
+				for _, endpoint := range endpoints {
+					Expect(endpoint.Refreshed).To(BeClosed())
+				}
+				Expect(cs.Endpoints()).To(HaveLen(4))
+			})
+		})
+	})
+
+	When("draining is enabled and an update removes an ordering organization with no open connections", func() {
+		BeforeEach(func() {
+			cs.SetDraining(true)
+			cs.Update(nil, map[string]orderers.OrdererOrg{
+				"org2": org2,
+			})
+		})
+
+		It("refreshes the removed endpoints rather than draining them", func() {
+			Expect(cs.DrainingEndpoints()).To(BeEmpty())
+			for _, endpoint := range endpoints {
+				Expect(endpoint.Refreshed).To(BeClosed())
+			}
+		})
+	})
+
 	When("an update modifies the global endpoints but does not affect the org endpoints", func() {
 		BeforeEach(func() {
 			cs.Update(nil, map[string]orderers.OrdererOrg{
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orderers
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// latencyTolerance is how much slower than the fastest endpoint an endpoint may be, and still be preferred.
+// Choosing among several comparable endpoints, rather than always the fastest, spreads the load.
+const latencyTolerance = 1.2
+
+// LatencyProber measures the round-trip time to an endpoint.
+type LatencyProber interface {
+	// Probe returns the round-trip time to the endpoint, or an error if it is not reachable.
+	Probe(ctx context.Context, endpoint *Endpoint) (time.Duration, error)
+}
+
+// TCPProber is a LatencyProber that measures the time it takes to establish a TCP connection to an endpoint.
+type TCPProber struct {
+	// Timeout bounds each probe. Zero means no timeout, other than the context's.
+	Timeout time.Duration
+}
+
+// Probe measures the time it takes to connect to the endpoint.
+func (p *TCPProber) Probe(ctx context.Context, endpoint *Endpoint) (time.Duration, error) {
+	dialer := net.Dialer{Timeout: p.Timeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint.Address)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	_ = conn.Close()
+	return rtt, nil
+}
+
+// StartLatencyProbing probes the latency of all the endpoints every interval, in the background, and makes the
+// endpoint selection prefer the fastest healthy endpoints. The returned function stops the probing, and discards
+// the measured latencies, so the selection no longer depends on them.
+// Probing is opt-in: ConnectionSourceFactory does not start it, so callers that want it start it on the
+// connection source they created, and stop it once the source is no longer used.
+func (cs *ConnectionSource) StartLatencyProbing(prober LatencyProber, interval time.Duration) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			cs.ProbeLatencies(ctx, prober)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		cancel()
+		<-done
+		cs.mutex.Lock()
+		defer cs.mutex.Unlock()
+		cs.latencies = nil
+		cs.unhealthy = nil
+	}
+}
+
+// ProbeLatencies probes the latency of all the endpoints once, and records the results. Endpoints whose probe
+// fails are considered unhealthy until a later probe succeeds.
+func (cs *ConnectionSource) ProbeLatencies(ctx context.Context, prober LatencyProber) {
+	endpoints := cs.Endpoints()
+	latencies := make(map[string]time.Duration, len(endpoints))
+	unhealthy := make(map[string]struct{})
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rtt, err := prober.Probe(ctx, endpoint)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				cs.logger.Debugf("Latency probe of endpoint [%s] failed: %s", endpoint.Address, err)
+				unhealthy[endpoint.Address] = struct{}{}
+				return
+			}
+			latencies[endpoint.Address] = rtt
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.latencies = latencies
+	cs.unhealthy = unhealthy
+}
+
+// preferFastest returns the healthy candidates whose latency is close to the fastest one, along with the
+// candidates that were not probed yet, e.g., the ones added by an update since the last probe. It returns all
+// the candidates if no latency was measured for any of them. It must be called with the mutex held.
+func (cs *ConnectionSource) preferFastest(candidates []*Endpoint) []*Endpoint {
+	fastest := time.Duration(-1)
+	for _, endpoint := range candidates {
+		if rtt, ok := cs.latencies[endpoint.Address]; ok && (fastest < 0 || rtt < fastest) {
+			fastest = rtt
+		}
+	}
+	if fastest < 0 {
+		return candidates
+	}
+
+	threshold := time.Duration(float64(fastest) * latencyTolerance)
+	preferred := make([]*Endpoint, 0, len(candidates))
+	for _, endpoint := range candidates {
+		rtt, measured := cs.latencies[endpoint.Address]
+		_, failed := cs.unhealthy[endpoint.Address]
+		if (measured && rtt <= threshold) || (!measured && !failed) {
+			preferred = append(preferred, endpoint)
+		}
+	}
+	return preferred
+}
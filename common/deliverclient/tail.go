@@ -0,0 +1,251 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/hyperledger/fabric-lib-go/common/flogging"
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/orderer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/hyperledger/fabric-x-common/protoutil"
+	"github.com/hyperledger/fabric-x-common/protoutil/identity"
+)
+
+const (
+	tailMinRetryDelay = 100 * time.Millisecond
+	tailMaxRetryDelay = 10 * time.Second
+)
+
+var tailLogger = flogging.MustGetLogger("common.deliverclient")
+
+// DeliverStreamSource opens deliver streams to an ordering service.
+type DeliverStreamSource interface {
+	// OpenDeliverStream opens a new deliver stream, which is torn down once ctx is done.
+	OpenDeliverStream(ctx context.Context) (orderer.AtomicBroadcast_DeliverClient, error)
+}
+
+// ConnStreamSource is a DeliverStreamSource that opens the deliver streams over a gRPC connection.
+type ConnStreamSource struct {
+	Conn *grpc.ClientConn
+}
+
+// OpenDeliverStream opens a new deliver stream over the connection.
+func (s *ConnStreamSource) OpenDeliverStream(ctx context.Context) (orderer.AtomicBroadcast_DeliverClient, error) {
+	return orderer.NewAtomicBroadcastClient(s.Conn).Deliver(ctx)
+}
+
+// TailOption configures the deliver requests issued by Tail.
+type TailOption func(*tailer)
+
+// WithHeadersOnly requests blocks without their data, i.e., only the headers and the metadata
+// with the signatures, which saves bandwidth for consumers that do not need the transactions.
+func WithHeadersOnly() TailOption {
+	return func(t *tailer) {
+		t.contentType = orderer.SeekInfo_HEADER_WITH_SIG
+	}
+}
+
+// WithContinuityCheck verifies that every delivered block follows the previous one in the hash chain,
+// using VerifyContinuity, and stops the tailing with an error on a gap or a fork.
+// The first delivered block is not checked, as its predecessor is not known.
+func WithContinuityCheck() TailOption {
+	return func(t *tailer) {
+		t.checkContinuity = true
+	}
+}
+
+// WithCheckpoint resumes the tailing from the block saved in the checkpoint, if any, instead of the start block,
+// and saves the progress to the checkpoint after each block is processed by the callback.
+// A failure to save the progress stops the tailing.
+func WithCheckpoint(checkpoint Checkpoint) TailOption {
+	return func(t *tailer) {
+		t.checkpoint = checkpoint
+	}
+}
+
+// Tail streams the blocks of a channel from startBlock onwards, and invokes fn for each block in order.
+// When the stream fails, it is re-opened from the next expected block after a backoff.
+// Tail returns the error returned by fn, which stops the tailing, or the context error once ctx is done.
+func Tail( //nolint:revive // argument-limit; max 4 but got 7
+	ctx context.Context,
+	source DeliverStreamSource,
+	channelID string,
+	signer identity.SignerSerializer,
+	startBlock uint64,
+	fn func(*common.Block) error,
+	opts ...TailOption,
+) error {
+	t := &tailer{
+		source:      source,
+		channelID:   channelID,
+		signer:      signer,
+		fn:          fn,
+		next:        startBlock,
+		contentType: orderer.SeekInfo_BLOCK,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.checkpoint != nil {
+		next, err := t.checkpoint.LoadCheckpoint()
+		switch {
+		case err == nil:
+			tailLogger.Infof("Resuming tailing channel %s from checkpoint at block %d", channelID, next)
+			t.next = next
+		case !errors.Is(err, ErrNoCheckpoint):
+			return errors.WithMessage(err, "could not load checkpoint")
+		}
+	}
+	return t.run(ctx)
+}
+
+type tailer struct {
+	source      DeliverStreamSource
+	channelID   string
+	signer      identity.SignerSerializer
+	fn          func(*common.Block) error
+	next        uint64
+	contentType orderer.SeekInfo_SeekContentType
+
+	checkContinuity bool
+	// prev is the last block delivered to the callback, if checkContinuity is set.
+	prev *common.Block
+
+	checkpoint Checkpoint
+}
+
+// stopError marks an error that stops the tailing, e.g., one returned by the tail callback.
+type stopError struct {
+	err error
+}
+
+func (e *stopError) Error() string {
+	return e.err.Error()
+}
+
+func (t *tailer) run(ctx context.Context) error {
+	delay := tailMinRetryDelay
+	for ctx.Err() == nil {
+		seekEnv, err := protoutil.CreateSignedEnvelope(
+			common.HeaderType_DELIVER_SEEK_INFO, t.channelID, t.signer, tailSeekInfo(t.next, t.contentType), int32(0), uint64(0),
+		)
+		if err != nil {
+			return errors.WithMessage(err, "could not create seek info envelope")
+		}
+
+		progressed, err := t.stream(ctx, seekEnv)
+		var stopErr *stopError
+		if errors.As(err, &stopErr) {
+			return stopErr.err
+		}
+		if progressed {
+			delay = tailMinRetryDelay
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		tailLogger.Warningf("Tailing channel %s failed, reconnecting from block %d in %v: %s",
+			t.channelID, t.next, delay, err)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, tailMaxRetryDelay)
+	}
+	return ctx.Err()
+}
+
+// stream opens a single deliver stream and consumes it until it fails.
+// It reports whether any block was delivered to the callback.
+func (t *tailer) stream(ctx context.Context, seekEnv *common.Envelope) (bool, error) {
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := t.source.OpenDeliverStream(streamCtx)
+	if err != nil {
+		return false, errors.WithMessage(err, "could not open deliver stream")
+	}
+	if err = stream.Send(seekEnv); err != nil {
+		return false, errors.WithMessage(err, "could not send seek info")
+	}
+
+	progressed := false
+	for {
+		block, err := receiveBlock(stream)
+		if err != nil {
+			return progressed, err
+		}
+		if block.Header.Number != t.next {
+			return progressed, errors.Errorf("expected block [%d] but got block [%d]", t.next, block.Header.Number)
+		}
+		if err = t.process(block); err != nil {
+			return progressed, err
+		}
+		progressed = true
+	}
+}
+
+// process delivers the next block to the callback and records the progress.
+// The returned errors stop the tailing.
+func (t *tailer) process(block *common.Block) error {
+	if t.checkContinuity && t.prev != nil {
+		if err := VerifyContinuity(t.prev, block); err != nil {
+			return &stopError{err: errors.WithMessage(err, "broken hash chain")}
+		}
+	}
+	if err := t.fn(block); err != nil {
+		return &stopError{err: err}
+	}
+	if t.checkContinuity {
+		t.prev = block
+	}
+	t.next++
+	if t.checkpoint != nil {
+		if err := t.checkpoint.SaveCheckpoint(t.next); err != nil {
+			return &stopError{err: errors.WithMessage(err, "could not save checkpoint")}
+		}
+	}
+	return nil
+}
+
+func receiveBlock(stream orderer.AtomicBroadcast_DeliverClient) (*common.Block, error) {
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not receive from deliver stream")
+	}
+	switch r := resp.Type.(type) {
+	case *orderer.DeliverResponse_Block:
+		if r.Block == nil || r.Block.Header == nil {
+			return nil, errors.New("received a block without a header")
+		}
+		return r.Block, nil
+	case *orderer.DeliverResponse_Status:
+		return nil, errors.Errorf("received status %s", r.Status)
+	default:
+		return nil, errors.Errorf("received unexpected response type %T", r)
+	}
+}
+
+func tailSeekInfo(start uint64, contentType orderer.SeekInfo_SeekContentType) *orderer.SeekInfo {
+	return &orderer.SeekInfo{
+		Start: &orderer.SeekPosition{
+			Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: start}},
+		},
+		Stop: &orderer.SeekPosition{
+			Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: math.MaxUint64}},
+		},
+		Behavior:    orderer.SeekInfo_BLOCK_UNTIL_READY,
+		ContentType: contentType,
+	}
+}
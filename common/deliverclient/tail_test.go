@@ -0,0 +1,265 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliverclient_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/hyperledger/fabric-protos-go-apiv2/orderer"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger/fabric-x-common/common/deliverclient"
+	"github.com/hyperledger/fabric-x-common/protoutil"
+	"github.com/hyperledger/fabric-x-common/protoutil/identity/mocks"
+)
+
+func TestTail(t *testing.T) {
+	t.Parallel()
+	server := &tailServer{lastBlock: 7}
+	source := startDeliverServer(t, server)
+
+	errStop := errors.New("stop")
+	var received []uint64
+	err := deliverclient.Tail(t.Context(), source, "mychannel", &mocks.SignerSerializer{}, 5,
+		func(block *common.Block) error {
+			received = append(received, block.Header.Number)
+			if len(received) == 3 {
+				return errStop
+			}
+			return nil
+		})
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []uint64{5, 6, 7}, received)
+	require.Equal(t, []uint64{5}, server.seekStarts())
+	require.Equal(t, []orderer.SeekInfo_SeekContentType{orderer.SeekInfo_BLOCK}, server.seekContentTypes())
+}
+
+func TestTailHeadersOnly(t *testing.T) {
+	t.Parallel()
+	server := &tailServer{lastBlock: 0}
+	source := startDeliverServer(t, server)
+
+	errStop := errors.New("stop")
+	err := deliverclient.Tail(t.Context(), source, "mychannel", &mocks.SignerSerializer{}, 0,
+		func(*common.Block) error {
+			return errStop
+		}, deliverclient.WithHeadersOnly())
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []orderer.SeekInfo_SeekContentType{orderer.SeekInfo_HEADER_WITH_SIG}, server.seekContentTypes())
+}
+
+func TestTailReconnects(t *testing.T) {
+	t.Parallel()
+	server := &tailServer{lastBlock: 2, failFirstAfter: 1}
+	source := startDeliverServer(t, server)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	var received []uint64
+	err := deliverclient.Tail(ctx, source, "mychannel", &mocks.SignerSerializer{}, 0,
+		func(block *common.Block) error {
+			received = append(received, block.Header.Number)
+			if len(received) == 3 {
+				cancel()
+			}
+			return nil
+		})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []uint64{0, 1, 2}, received)
+	require.Equal(t, []uint64{0, 1}, server.seekStarts())
+}
+
+func TestTailContinuityCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("hash chain", func(t *testing.T) {
+		t.Parallel()
+		server := &tailServer{lastBlock: 7, chained: true}
+		source := startDeliverServer(t, server)
+
+		errStop := errors.New("stop")
+		var received []uint64
+		err := deliverclient.Tail(t.Context(), source, "mychannel", &mocks.SignerSerializer{}, 5,
+			func(block *common.Block) error {
+				received = append(received, block.Header.Number)
+				if len(received) == 3 {
+					return errStop
+				}
+				return nil
+			}, deliverclient.WithContinuityCheck())
+		require.ErrorIs(t, err, errStop)
+		require.Equal(t, []uint64{5, 6, 7}, received)
+	})
+
+	t.Run("broken hash chain", func(t *testing.T) {
+		t.Parallel()
+		server := &tailServer{lastBlock: 7}
+		source := startDeliverServer(t, server)
+
+		var received []uint64
+		err := deliverclient.Tail(t.Context(), source, "mychannel", &mocks.SignerSerializer{}, 5,
+			func(block *common.Block) error {
+				received = append(received, block.Header.Number)
+				return nil
+			}, deliverclient.WithContinuityCheck())
+		require.ErrorContains(t, err, "broken hash chain: previous hash of block [6]")
+		require.Equal(t, []uint64{5}, received)
+	})
+}
+
+func TestTailCheckpoint(t *testing.T) {
+	t.Parallel()
+	server := &tailServer{lastBlock: 7}
+	source := startDeliverServer(t, server)
+	checkpoint := &deliverclient.FileCheckpoint{Path: filepath.Join(t.TempDir(), "checkpoint")}
+	require.NoError(t, checkpoint.SaveCheckpoint(5))
+
+	errStop := errors.New("stop")
+	var received []uint64
+	err := deliverclient.Tail(t.Context(), source, "mychannel", &mocks.SignerSerializer{}, 0,
+		func(block *common.Block) error {
+			received = append(received, block.Header.Number)
+			if block.Header.Number == 7 {
+				return errStop
+			}
+			return nil
+		}, deliverclient.WithCheckpoint(checkpoint))
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []uint64{5, 6, 7}, received)
+	require.Equal(t, []uint64{5}, server.seekStarts())
+
+	// Block 7 was not processed successfully, so it is the next one to process.
+	next, err := checkpoint.LoadCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), next)
+}
+
+func TestTailCheckpointStartsFromStartBlock(t *testing.T) {
+	t.Parallel()
+	server := &tailServer{lastBlock: 7}
+	source := startDeliverServer(t, server)
+	checkpoint := &deliverclient.FileCheckpoint{Path: filepath.Join(t.TempDir(), "checkpoint")}
+
+	errStop := errors.New("stop")
+	err := deliverclient.Tail(t.Context(), source, "mychannel", &mocks.SignerSerializer{}, 3,
+		func(block *common.Block) error {
+			if block.Header.Number == 4 {
+				return errStop
+			}
+			return nil
+		}, deliverclient.WithCheckpoint(checkpoint))
+	require.ErrorIs(t, err, errStop)
+	require.Equal(t, []uint64{3}, server.seekStarts())
+
+	next, err := checkpoint.LoadCheckpoint()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), next)
+}
+
+// tailServer streams the blocks from the requested start up to lastBlock, and then blocks until the stream is closed.
+type tailServer struct {
+	orderer.UnimplementedAtomicBroadcastServer
+	lastBlock uint64
+	// chained links the blocks in a hash chain. Otherwise, the blocks have no previous hash.
+	chained bool
+	// failFirstAfter fails the first stream after sending that many blocks. Zero means never fail.
+	failFirstAfter int
+
+	lock         sync.Mutex
+	starts       []uint64
+	contentTypes []orderer.SeekInfo_SeekContentType
+}
+
+func (s *tailServer) Deliver(stream orderer.AtomicBroadcast_DeliverServer) error {
+	env, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	seekInfo := &orderer.SeekInfo{}
+	if err = proto.Unmarshal(payload.Data, seekInfo); err != nil {
+		return err
+	}
+	start := seekInfo.Start.GetSpecified().Number
+
+	s.lock.Lock()
+	s.starts = append(s.starts, start)
+	s.contentTypes = append(s.contentTypes, seekInfo.ContentType)
+	failAfter := 0
+	if len(s.starts) == 1 {
+		failAfter = s.failFirstAfter
+	}
+	s.lock.Unlock()
+
+	for i, num := 0, start; num <= s.lastBlock; i, num = i+1, num+1 {
+		if failAfter > 0 && i == failAfter {
+			return errors.New("stream failure")
+		}
+		err = stream.Send(&orderer.DeliverResponse{
+			Type: &orderer.DeliverResponse_Block{Block: s.block(num)},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+func (s *tailServer) block(num uint64) *common.Block {
+	if !s.chained {
+		return protoutil.NewBlock(num, nil)
+	}
+	block := protoutil.NewBlock(0, nil)
+	for i := uint64(1); i <= num; i++ {
+		block = protoutil.NewBlock(i, protoutil.BlockHeaderHash(block.Header))
+	}
+	return block
+}
+
+func (s *tailServer) seekStarts() []uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.starts
+}
+
+func (s *tailServer) seekContentTypes() []orderer.SeekInfo_SeekContentType {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.contentTypes
+}
+
+func startDeliverServer(t *testing.T, server orderer.AtomicBroadcastServer) *deliverclient.ConnStreamSource {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcServer := grpc.NewServer()
+	orderer.RegisterAtomicBroadcastServer(grpcServer, server)
+	go func() {
+		_ = grpcServer.Serve(lis)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return &deliverclient.ConnStreamSource{Conn: conn}
+}
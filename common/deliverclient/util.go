@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package deliverclient
 
 import (
+	"bytes"
+	"encoding/hex"
+
 	"github.com/hyperledger/fabric-protos-go-apiv2/common"
 	"github.com/pkg/errors"
 
@@ -54,3 +57,21 @@ func ConfigFromBlock(block *common.Block) (*common.ConfigEnvelope, error) {
 	}
 	return configEnvelope, nil
 }
+
+// VerifyContinuity checks that next directly follows prev in the hash chain, i.e., that next is numbered
+// right after prev, and that its previous hash is the hash of the header of prev.
+func VerifyContinuity(prev, next *common.Block) error {
+	if prev.GetHeader() == nil || next.GetHeader() == nil {
+		return errors.New("block without a header")
+	}
+	if next.Header.Number != prev.Header.Number+1 {
+		return errors.Errorf("block [%d] does not follow block [%d]", next.Header.Number, prev.Header.Number)
+	}
+	prevHash := protoutil.BlockHeaderHash(prev.Header)
+	if !bytes.Equal(next.Header.PreviousHash, prevHash) {
+		return errors.Errorf("previous hash of block [%d] is %s, but the hash of block [%d] is %s",
+			next.Header.Number, hex.EncodeToString(next.Header.PreviousHash),
+			prev.Header.Number, hex.EncodeToString(prevHash))
+	}
+	return nil
+}
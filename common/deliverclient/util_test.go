@@ -105,3 +105,19 @@ func TestConfigFromBlockBadInput(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyContinuity(t *testing.T) {
+	t.Parallel()
+	prev := protoutil.NewBlock(4, []byte("hash of block 3"))
+	next := protoutil.NewBlock(5, protoutil.BlockHeaderHash(prev.Header))
+	require.NoError(t, deliverclient.VerifyContinuity(prev, next))
+
+	fork := protoutil.NewBlock(5, []byte("another hash"))
+	require.ErrorContains(t, deliverclient.VerifyContinuity(prev, fork),
+		"previous hash of block [5] is 616e6f746865722068617368, but the hash of block [4] is")
+
+	gap := protoutil.NewBlock(6, protoutil.BlockHeaderHash(prev.Header))
+	require.EqualError(t, deliverclient.VerifyContinuity(prev, gap), "block [6] does not follow block [4]")
+
+	require.EqualError(t, deliverclient.VerifyContinuity(prev, &common.Block{}), "block without a header")
+}
@@ -7,8 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package genesis
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
 	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/hyperledger/fabric-x-common/common/crypto"
 	"github.com/hyperledger/fabric-x-common/protoutil"
 )
 
@@ -27,6 +34,8 @@ type Factory interface {
 
 type factory struct {
 	channelGroup *cb.ConfigGroup
+	// timestamp is set for deterministic factories.
+	timestamp *time.Time
 }
 
 // NewFactoryImpl creates a new Factory.
@@ -34,13 +43,34 @@ func NewFactoryImpl(channelGroup *cb.ConfigGroup) Factory {
 	return &factory{channelGroup: channelGroup}
 }
 
+// NewDeterministicFactory creates a Factory whose blocks only depend on the channel group, the channel ID,
+// and the given timestamp, so generating a block again yields the same bytes and hash.
+// The timestamp is truncated to seconds, as with regular genesis blocks, and the nonce is derived from
+// the channel ID and the timestamp instead of being random.
+func NewDeterministicFactory(channelGroup *cb.ConfigGroup, timestamp time.Time) Factory {
+	return &factory{channelGroup: channelGroup, timestamp: &timestamp}
+}
+
 // Block constructs and returns a genesis block for a given channel ID.
 func (f *factory) Block(channelID string) *cb.Block {
 	payloadChannelHeader := protoutil.MakeChannelHeader(cb.HeaderType_CONFIG, msgVersion, channelID, epoch)
-	payloadSignatureHeader := protoutil.MakeSignatureHeader(nil, protoutil.CreateNonceOrPanic())
+	nonce := protoutil.CreateNonceOrPanic()
+	configEnvelope := &cb.ConfigEnvelope{Config: &cb.Config{ChannelGroup: f.channelGroup}}
+	configEnvelopeBytes := protoutil.MarshalOrPanic(configEnvelope)
+	if f.timestamp != nil {
+		payloadChannelHeader.Timestamp = timestamppb.New(f.timestamp.Truncate(time.Second))
+		nonce = deterministicNonce(channelID, payloadChannelHeader.Timestamp)
+		// The config holds maps, whose default encoding order is not stable.
+		var err error
+		configEnvelopeBytes, err = proto.MarshalOptions{Deterministic: true}.Marshal(configEnvelope)
+		if err != nil {
+			panic(err)
+		}
+	}
+	payloadSignatureHeader := protoutil.MakeSignatureHeader(nil, nonce)
 	protoutil.SetTxID(payloadChannelHeader, payloadSignatureHeader)
 	payloadHeader := protoutil.MakePayloadHeader(payloadChannelHeader, payloadSignatureHeader)
-	payload := &cb.Payload{Header: payloadHeader, Data: protoutil.MarshalOrPanic(&cb.ConfigEnvelope{Config: &cb.Config{ChannelGroup: f.channelGroup}})}
+	payload := &cb.Payload{Header: payloadHeader, Data: configEnvelopeBytes}
 	envelope := &cb.Envelope{Payload: protoutil.MarshalOrPanic(payload), Signature: nil}
 
 	block := protoutil.NewBlock(0, nil)
@@ -56,3 +86,11 @@ func (f *factory) Block(channelID string) *cb.Block {
 	})
 	return block
 }
+
+// deterministicNonce derives a nonce from the channel ID and the timestamp of a genesis block.
+func deterministicNonce(channelID string, timestamp *timestamppb.Timestamp) []byte {
+	h := sha256.New()
+	h.Write([]byte(channelID))
+	h.Write(binary.BigEndian.AppendUint64(nil, uint64(timestamp.Seconds))) //nolint:gosec // int64 -> uint64.
+	return h.Sum(nil)[:crypto.NonceSize]
+}
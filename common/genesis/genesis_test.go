@@ -8,6 +8,7 @@ package genesis
 
 import (
 	"testing"
+	"time"
 
 	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
 	"github.com/stretchr/testify/require"
@@ -46,3 +47,32 @@ func TestFactory(t *testing.T) {
 		require.Equal(t, uint64(0), lastConfig.Index)
 	})
 }
+
+func TestDeterministicFactory(t *testing.T) {
+	t.Parallel()
+	channelGroup := protoutil.NewConfigGroup()
+	channelGroup.Groups["Orderer"] = protoutil.NewConfigGroup()
+	channelGroup.Groups["Application"] = protoutil.NewConfigGroup()
+	channelGroup.Values["Consortium"] = &cb.ConfigValue{Value: []byte("consortium")}
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	block := NewDeterministicFactory(channelGroup, timestamp).Block("testchannelid")
+	for range 10 {
+		again := NewDeterministicFactory(proto.CloneOf(channelGroup), timestamp).Block("testchannelid")
+		require.True(t, proto.Equal(block, again))
+	}
+
+	configEnv, err := protoutil.ExtractEnvelope(block, 0)
+	require.NoError(t, err)
+	payload, err := protoutil.UnmarshalPayload(configEnv.Payload)
+	require.NoError(t, err)
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	require.Equal(t, timestamp, channelHeader.Timestamp.AsTime())
+	require.NotEmpty(t, channelHeader.TxId)
+
+	other := NewDeterministicFactory(channelGroup, timestamp.Add(time.Second)).Block("testchannelid")
+	require.NotEqual(t, block.Header.DataHash, other.Header.DataHash)
+	other = NewDeterministicFactory(channelGroup, timestamp).Block("otherchannel")
+	require.NotEqual(t, block.Header.DataHash, other.Header.DataHash)
+}
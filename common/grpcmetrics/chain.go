@@ -0,0 +1,32 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcmetrics
+
+import (
+	"google.golang.org/grpc"
+)
+
+// Interceptors are the caller's interceptors of a gRPC server, e.g., logging interceptors, which
+// ChainServerInterceptors installs inside the metrics interceptors, in the given order.
+type Interceptors struct {
+	Unary  []grpc.UnaryServerInterceptor
+	Stream []grpc.StreamServerInterceptor
+}
+
+// ChainServerInterceptors returns the server options that install the metrics interceptors of a gRPC server,
+// followed by the caller's interceptors. The metrics interceptors are outermost, so the recorded request
+// durations account for the whole handling of the request, the caller's interceptors included.
+func ChainServerInterceptors(
+	um *UnaryMetrics, sm *StreamMetrics, inner Interceptors, opts ...InterceptorOption,
+) []grpc.ServerOption {
+	unary := append([]grpc.UnaryServerInterceptor{UnaryServerInterceptor(um, opts...)}, inner.Unary...)
+	stream := append([]grpc.StreamServerInterceptor{StreamServerInterceptor(sm, opts...)}, inner.Stream...)
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
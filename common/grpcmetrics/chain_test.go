@@ -0,0 +1,178 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcmetrics_test
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/hyperledger/fabric-lib-go/common/metrics/metricsfakes"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/hyperledger/fabric-x-common/common/grpcmetrics"
+	"github.com/hyperledger/fabric-x-common/common/grpcmetrics/fakes"
+	"github.com/hyperledger/fabric-x-common/common/grpcmetrics/testpb"
+	"github.com/hyperledger/fabric-x-common/tools/test"
+)
+
+var _ = ginkgo.Describe("ChainServerInterceptors", func() {
+	var (
+		echoServiceClient testpb.EchoServiceClient
+
+		fakeRequestDuration   *metricsfakes.Histogram
+		fakeRequestsReceived  *metricsfakes.Counter
+		fakeRequestsCompleted *metricsfakes.Counter
+		fakeMessagesSent      *metricsfakes.Counter
+		fakeMessagesReceived  *metricsfakes.Counter
+
+		mutex      sync.Mutex
+		innerCalls []string
+
+		listener        net.Listener
+		serveCompleteCh chan error
+	)
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		fakeEchoService := &fakes.EchoServiceServer{}
+		fakeEchoService.EchoStub = func(ctx context.Context, msg *testpb.Message) (*testpb.Message, error) {
+			msg.Sequence++
+			return msg, nil
+		}
+		fakeEchoService.EchoStreamStub = func(stream testpb.EchoService_EchoStreamServer) error {
+			msg, err := stream.Recv()
+			if err != nil {
+				return err
+			}
+			msg.Sequence++
+			return stream.Send(msg)
+		}
+
+		fakeRequestDuration = &metricsfakes.Histogram{}
+		fakeRequestDuration.WithReturns(fakeRequestDuration)
+		fakeRequestsReceived = &metricsfakes.Counter{}
+		fakeRequestsReceived.WithReturns(fakeRequestsReceived)
+		fakeRequestsCompleted = &metricsfakes.Counter{}
+		fakeRequestsCompleted.WithReturns(fakeRequestsCompleted)
+		fakeMessagesSent = &metricsfakes.Counter{}
+		fakeMessagesSent.WithReturns(fakeMessagesSent)
+		fakeMessagesReceived = &metricsfakes.Counter{}
+		fakeMessagesReceived.WithReturns(fakeMessagesReceived)
+
+		unaryMetrics := &grpcmetrics.UnaryMetrics{
+			RequestDuration:   fakeRequestDuration,
+			RequestsReceived:  fakeRequestsReceived,
+			RequestsCompleted: fakeRequestsCompleted,
+		}
+		streamMetrics := &grpcmetrics.StreamMetrics{
+			RequestDuration:   fakeRequestDuration,
+			RequestsReceived:  fakeRequestsReceived,
+			RequestsCompleted: fakeRequestsCompleted,
+			MessagesSent:      fakeMessagesSent,
+			MessagesReceived:  fakeMessagesReceived,
+		}
+
+		mutex.Lock()
+		innerCalls = nil
+		mutex.Unlock()
+		record := func(call string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			innerCalls = append(innerCalls, call)
+		}
+		inner := grpcmetrics.Interceptors{
+			Unary: []grpc.UnaryServerInterceptor{func(
+				ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+			) (any, error) {
+				resp, err := handler(ctx, req)
+				record(info.FullMethod)
+				return resp, err
+			}},
+			Stream: []grpc.StreamServerInterceptor{func(
+				srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+			) error {
+				err := handler(srv, ss)
+				record(info.FullMethod)
+				return err
+			}},
+		}
+		server := grpc.NewServer(grpcmetrics.ChainServerInterceptors(unaryMetrics, streamMetrics, inner)...)
+
+		testpb.RegisterEchoServiceServer(server, fakeEchoService)
+		serveCompleteCh = make(chan error, 1)
+		go func() { serveCompleteCh <- server.Serve(listener) }()
+
+		cc, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		echoServiceClient = testpb.NewEchoServiceClient(cc)
+	})
+
+	ginkgo.AfterEach(func() {
+		err := listener.Close()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Eventually(serveCompleteCh).Should(gomega.Receive())
+	})
+
+	recordedCalls := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string(nil), innerCalls...)
+	}
+
+	// The metrics interceptors wrap the caller's ones: the request is received
+	// before the caller's interceptor completes, and completed after.
+	expectMetricsOutermost := func() {
+		fakeRequestsReceived.AddStub = func(float64) {
+			defer ginkgo.GinkgoRecover()
+			gomega.Expect(recordedCalls()).To(gomega.BeEmpty())
+		}
+		fakeRequestsCompleted.AddStub = func(float64) {
+			defer ginkgo.GinkgoRecover()
+			gomega.Expect(recordedCalls()).To(gomega.HaveLen(1))
+		}
+	}
+
+	ginkgo.It("records the metrics and runs the caller's interceptors on a unary call", func() {
+		expectMetricsOutermost()
+
+		resp, err := echoServiceClient.Echo(context.Background(), &testpb.Message{Message: "yo"})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(resp).To(test.ProtoEqual(&testpb.Message{Message: "yo", Sequence: 1}))
+
+		gomega.Expect(fakeRequestsReceived.AddCallCount()).To(gomega.Equal(1))
+		gomega.Expect(fakeRequestsCompleted.AddCallCount()).To(gomega.Equal(1))
+		gomega.Expect(fakeRequestDuration.ObserveCallCount()).To(gomega.Equal(1))
+
+		gomega.Expect(recordedCalls()).To(gomega.Equal([]string{"/testpb.EchoService/Echo"}))
+	})
+
+	ginkgo.It("records the metrics and runs the caller's interceptors on a streaming call", func() {
+		expectMetricsOutermost()
+
+		streamClient, err := echoServiceClient.EchoStream(context.Background())
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(streamClient.Send(&testpb.Message{Message: "hello"})).To(gomega.Succeed())
+		msg, err := streamClient.Recv()
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(msg).To(test.ProtoEqual(&testpb.Message{Message: "hello", Sequence: 1}))
+		gomega.Eventually(fakeRequestsCompleted.AddCallCount).Should(gomega.Equal(1))
+
+		gomega.Expect(fakeRequestsReceived.AddCallCount()).To(gomega.Equal(1))
+		gomega.Expect(fakeMessagesReceived.AddCallCount()).To(gomega.Equal(1))
+		gomega.Expect(fakeMessagesSent.AddCallCount()).To(gomega.Equal(1))
+		gomega.Expect(fakeRequestDuration.ObserveCallCount()).To(gomega.Equal(1))
+
+		gomega.Expect(recordedCalls()).To(gomega.Equal([]string{"/testpb.EchoService/EchoStream"}))
+	})
+})
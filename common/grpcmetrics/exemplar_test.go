@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcmetrics_test
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric-lib-go/common/metrics"
+	"github.com/hyperledger/fabric-lib-go/common/metrics/metricsfakes"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"github.com/hyperledger/fabric-x-common/common/grpcmetrics"
+)
+
+type traceIDKey struct{}
+
+// exemplarHistogram is a fake histogram of a metrics backend that supports exemplars.
+type exemplarHistogram struct {
+	*metricsfakes.Histogram
+	values    []float64
+	exemplars []map[string]string
+}
+
+func (h *exemplarHistogram) With(...string) metrics.Histogram {
+	return h
+}
+
+func (h *exemplarHistogram) ObserveWithExemplar(value float64, exemplar map[string]string) {
+	h.values = append(h.values, value)
+	h.exemplars = append(h.exemplars, exemplar)
+}
+
+var _ = ginkgo.Describe("Exemplars", func() {
+	var (
+		unaryMetrics *grpcmetrics.UnaryMetrics
+		extractor    func(ctx context.Context) (string, bool)
+		info         *grpc.UnaryServerInfo
+		handler      grpc.UnaryHandler
+	)
+
+	ginkgo.BeforeEach(func() {
+		fakeCounter := &metricsfakes.Counter{}
+		fakeCounter.WithReturns(fakeCounter)
+		unaryMetrics = &grpcmetrics.UnaryMetrics{
+			RequestsReceived:  fakeCounter,
+			RequestsCompleted: fakeCounter,
+		}
+		extractor = func(ctx context.Context) (string, bool) {
+			traceID, ok := ctx.Value(traceIDKey{}).(string)
+			return traceID, ok
+		}
+		info = &grpc.UnaryServerInfo{FullMethod: "/testpb.EchoService/Echo"}
+		handler = func(context.Context, any) (any, error) {
+			return nil, nil
+		}
+	})
+
+	ginkgo.It("attaches the trace ID to the observed duration", func() {
+		histogram := &exemplarHistogram{Histogram: &metricsfakes.Histogram{}}
+		unaryMetrics.RequestDuration = histogram
+		interceptor := grpcmetrics.UnaryServerInterceptor(unaryMetrics, grpcmetrics.WithExemplarExtractor(extractor))
+
+		ctx := context.WithValue(context.Background(), traceIDKey{}, "4bf92f3577b34da6")
+		_, err := interceptor(ctx, nil, info, handler)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(histogram.exemplars).To(gomega.Equal([]map[string]string{
+			{grpcmetrics.TraceIDLabel: "4bf92f3577b34da6"},
+		}))
+		gomega.Expect(histogram.values).To(gomega.HaveLen(1))
+		gomega.Expect(histogram.ObserveCallCount()).To(gomega.Equal(0))
+	})
+
+	ginkgo.It("observes without an exemplar when there is no trace ID", func() {
+		histogram := &exemplarHistogram{Histogram: &metricsfakes.Histogram{}}
+		unaryMetrics.RequestDuration = histogram
+		interceptor := grpcmetrics.UnaryServerInterceptor(unaryMetrics, grpcmetrics.WithExemplarExtractor(extractor))
+
+		_, err := interceptor(context.Background(), nil, info, handler)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(histogram.exemplars).To(gomega.BeEmpty())
+		gomega.Expect(histogram.ObserveCallCount()).To(gomega.Equal(1))
+	})
+
+	ginkgo.It("is a no-op when the histogram does not support exemplars", func() {
+		histogram := &metricsfakes.Histogram{}
+		histogram.WithReturns(histogram)
+		unaryMetrics.RequestDuration = histogram
+		interceptor := grpcmetrics.UnaryServerInterceptor(unaryMetrics, grpcmetrics.WithExemplarExtractor(extractor))
+
+		ctx := context.WithValue(context.Background(), traceIDKey{}, "4bf92f3577b34da6")
+		_, err := interceptor(ctx, nil, info, handler)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(histogram.ObserveCallCount()).To(gomega.Equal(1))
+	})
+})
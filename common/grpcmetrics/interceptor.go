@@ -9,6 +9,7 @@ package grpcmetrics
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-lib-go/common/metrics"
@@ -16,15 +17,68 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// TraceIDLabel is the exemplar label that holds the trace ID of an observed request.
+const TraceIDLabel = "trace_id"
+
+// ExemplarObserver is implemented by histograms of metrics backends that support exemplars.
+type ExemplarObserver interface {
+	ObserveWithExemplar(value float64, exemplar map[string]string)
+}
+
+// InterceptorOption configures the metrics interceptors.
+type InterceptorOption func(*interceptorConfig)
+
+type interceptorConfig struct {
+	exemplarExtractor func(ctx context.Context) (traceID string, ok bool)
+	labelSanitizer    func(string) string
+}
+
+// WithLabelSanitizer sets the transformation applied to the service and method labels.
+// By default, dots are replaced with underscores, as Prometheus expects.
+func WithLabelSanitizer(sanitize func(string) string) InterceptorOption {
+	return func(c *interceptorConfig) {
+		c.labelSanitizer = sanitize
+	}
+}
+
+// WithExemplarExtractor attaches the trace ID returned by extract to the observed request durations,
+// as an exemplar labeled TraceIDLabel. It has no effect for histograms that do not implement
+// ExemplarObserver, or when extract reports no trace ID.
+func WithExemplarExtractor(extract func(ctx context.Context) (traceID string, ok bool)) InterceptorOption {
+	return func(c *interceptorConfig) {
+		c.exemplarExtractor = extract
+	}
+}
+
+func newInterceptorConfig(opts []InterceptorOption) *interceptorConfig {
+	c := &interceptorConfig{labelSanitizer: prometheusLabel}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// observeDuration observes the duration of a request, with the request's trace ID as an exemplar if available.
+func (c *interceptorConfig) observeDuration(ctx context.Context, h metrics.Histogram, duration time.Duration) {
+	if eo, ok := h.(ExemplarObserver); ok && c.exemplarExtractor != nil {
+		if traceID, ok := c.exemplarExtractor(ctx); ok {
+			eo.ObserveWithExemplar(duration.Seconds(), map[string]string{TraceIDLabel: traceID})
+			return
+		}
+	}
+	h.Observe(duration.Seconds())
+}
+
 type UnaryMetrics struct {
 	RequestDuration   metrics.Histogram
 	RequestsReceived  metrics.Counter
 	RequestsCompleted metrics.Counter
 }
 
-func UnaryServerInterceptor(um *UnaryMetrics) grpc.UnaryServerInterceptor {
+func UnaryServerInterceptor(um *UnaryMetrics, opts ...InterceptorOption) grpc.UnaryServerInterceptor {
+	config := newInterceptorConfig(opts)
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		service, method := serviceMethod(info.FullMethod)
+		service, method := config.serviceMethod(info.FullMethod)
 		um.RequestsReceived.With("service", service, "method", method).Add(1)
 
 		startTime := time.Now()
@@ -32,9 +86,9 @@ func UnaryServerInterceptor(um *UnaryMetrics) grpc.UnaryServerInterceptor {
 		st, _ := status.FromError(err)
 		duration := time.Since(startTime)
 
-		um.RequestDuration.With(
+		config.observeDuration(ctx, um.RequestDuration.With(
 			"service", service, "method", method, "code", st.Code().String(),
-		).Observe(duration.Seconds())
+		), duration)
 		um.RequestsCompleted.With("service", service, "method", method, "code", st.Code().String()).Add(1)
 
 		return resp, err
@@ -47,12 +101,18 @@ type StreamMetrics struct {
 	RequestsCompleted metrics.Counter
 	MessagesSent      metrics.Counter
 	MessagesReceived  metrics.Counter
+
+	// MaxMessagesReceived is optional. When set, it tracks, per method, the
+	// maximum number of messages received in a single stream.
+	MaxMessagesReceived metrics.Gauge
 }
 
-func StreamServerInterceptor(sm *StreamMetrics) grpc.StreamServerInterceptor {
+func StreamServerInterceptor(sm *StreamMetrics, opts ...InterceptorOption) grpc.StreamServerInterceptor {
+	config := newInterceptorConfig(opts)
+	maxMessages := &maxMessagesTracker{max: map[string]int{}}
 	return func(svc interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		sm := sm
-		service, method := serviceMethod(info.FullMethod)
+		service, method := config.serviceMethod(info.FullMethod)
 		sm.RequestsReceived.With("service", service, "method", method).Add(1)
 
 		wrappedStream := &serverStream{
@@ -60,34 +120,65 @@ func StreamServerInterceptor(sm *StreamMetrics) grpc.StreamServerInterceptor {
 			messagesSent:     sm.MessagesSent.With("service", service, "method", method),
 			messagesReceived: sm.MessagesReceived.With("service", service, "method", method),
 		}
+		if sm.MaxMessagesReceived != nil {
+			gauge := sm.MaxMessagesReceived.With("service", service, "method", method)
+			wrappedStream.onReceived = func(count int) {
+				maxMessages.observe(info.FullMethod, count, gauge)
+			}
+		}
 
 		startTime := time.Now()
 		err := handler(svc, wrappedStream)
 		st, _ := status.FromError(err)
 		duration := time.Since(startTime)
 
-		sm.RequestDuration.With(
+		config.observeDuration(stream.Context(), sm.RequestDuration.With(
 			"service", service, "method", method, "code", st.Code().String(),
-		).Observe(duration.Seconds())
+		), duration)
 		sm.RequestsCompleted.With("service", service, "method", method, "code", st.Code().String()).Add(1)
 
 		return err
 	}
 }
 
-func serviceMethod(fullMethod string) (service, method string) {
-	normalizedMethod := strings.Replace(fullMethod, ".", "_", -1)
-	parts := strings.SplitN(normalizedMethod, "/", -1)
+func (c *interceptorConfig) serviceMethod(fullMethod string) (service, method string) {
+	parts := strings.SplitN(fullMethod, "/", -1)
 	if len(parts) != 3 {
 		return "unknown", "unknown"
 	}
-	return parts[1], parts[2]
+	return c.labelSanitizer(parts[1]), c.labelSanitizer(parts[2])
+}
+
+// prometheusLabel is the default label sanitizer, which replaces dots with underscores.
+func prometheusLabel(label string) string {
+	return strings.ReplaceAll(label, ".", "_")
+}
+
+// maxMessagesTracker keeps the maximum number of messages received in a single stream, per method.
+type maxMessagesTracker struct {
+	lock sync.Mutex
+	max  map[string]int
+}
+
+// observe updates the gauge if count exceeds the maximum observed so far for the method.
+func (t *maxMessagesTracker) observe(fullMethod string, count int, gauge metrics.Gauge) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if count <= t.max[fullMethod] {
+		return
+	}
+	t.max[fullMethod] = count
+	gauge.Set(float64(count))
 }
 
 type serverStream struct {
 	grpc.ServerStream
 	messagesSent     metrics.Counter
 	messagesReceived metrics.Counter
+
+	// onReceived, if set, is called with the number of messages received so far in the stream.
+	onReceived    func(count int)
+	receivedCount int
 }
 
 func (ss *serverStream) SendMsg(msg interface{}) error {
@@ -99,6 +190,10 @@ func (ss *serverStream) RecvMsg(msg interface{}) error {
 	err := ss.ServerStream.RecvMsg(msg)
 	if err == nil {
 		ss.messagesReceived.Add(1)
+		if ss.onReceived != nil {
+			ss.receivedCount++
+			ss.onReceived(ss.receivedCount)
+		}
 	}
 	return err
 }
@@ -276,6 +276,43 @@ var _ = ginkgo.Describe("Interceptor", func() {
 			}
 		})
 
+		ginkgo.Context("when max messages received is tracked", func() {
+			var fakeMaxMessagesReceived *metricsfakes.Gauge
+
+			ginkgo.BeforeEach(func() {
+				fakeMaxMessagesReceived = &metricsfakes.Gauge{}
+				fakeMaxMessagesReceived.WithReturns(fakeMaxMessagesReceived)
+				streamMetrics.MaxMessagesReceived = fakeMaxMessagesReceived
+			})
+
+			ginkgo.It("records the maximum number of messages received in a single stream", func() {
+				streamClient, err := echoServiceClient.EchoStream(context.Background())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				streamMessages(streamClient)
+
+				gomega.Expect(fakeMaxMessagesReceived.WithArgsForCall(0)).To(gomega.Equal([]string{
+					"service", "testpb_EchoService",
+					"method", "EchoStream",
+				}))
+				gomega.Expect(fakeMaxMessagesReceived.SetCallCount()).To(gomega.Equal(2))
+				gomega.Expect(fakeMaxMessagesReceived.SetArgsForCall(0)).To(gomega.BeNumerically("~", 1.0))
+				gomega.Expect(fakeMaxMessagesReceived.SetArgsForCall(1)).To(gomega.BeNumerically("~", 2.0))
+
+				// A shorter stream does not lower the maximum.
+				streamClient, err = echoServiceClient.EchoStream(context.Background())
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				err = streamClient.Send(&testpb.Message{Message: "hello"})
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				_, err = streamClient.Recv()
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(streamClient.CloseSend()).To(gomega.Succeed())
+				_, err = streamClient.Recv()
+				gomega.Expect(err).To(gomega.Equal(io.EOF))
+
+				gomega.Expect(fakeMaxMessagesReceived.SetCallCount()).To(gomega.Equal(2))
+			})
+		})
+
 		ginkgo.Context("when stream recv returns an error", func() {
 			var errCh chan error
 
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcmetrics_test
+
+import (
+	"context"
+
+	"github.com/hyperledger/fabric-lib-go/common/metrics/metricsfakes"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"github.com/hyperledger/fabric-x-common/common/grpcmetrics"
+)
+
+var _ = ginkgo.Describe("Label sanitizer", func() {
+	var (
+		fakeCounter  *metricsfakes.Counter
+		unaryMetrics *grpcmetrics.UnaryMetrics
+		info         *grpc.UnaryServerInfo
+		handler      grpc.UnaryHandler
+	)
+
+	ginkgo.BeforeEach(func() {
+		fakeCounter = &metricsfakes.Counter{}
+		fakeCounter.WithReturns(fakeCounter)
+		fakeHistogram := &metricsfakes.Histogram{}
+		fakeHistogram.WithReturns(fakeHistogram)
+		unaryMetrics = &grpcmetrics.UnaryMetrics{
+			RequestDuration:   fakeHistogram,
+			RequestsReceived:  fakeCounter,
+			RequestsCompleted: fakeCounter,
+		}
+		info = &grpc.UnaryServerInfo{FullMethod: "/testpb.EchoService/Echo"}
+		handler = func(context.Context, any) (any, error) {
+			return nil, nil
+		}
+	})
+
+	ginkgo.It("replaces dots with underscores by default", func() {
+		interceptor := grpcmetrics.UnaryServerInterceptor(unaryMetrics)
+		_, err := interceptor(context.Background(), nil, info, handler)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(fakeCounter.WithArgsForCall(0)).To(gomega.Equal([]string{
+			"service", "testpb_EchoService", "method", "Echo",
+		}))
+	})
+
+	ginkgo.It("applies a custom sanitizer", func() {
+		interceptor := grpcmetrics.UnaryServerInterceptor(unaryMetrics,
+			grpcmetrics.WithLabelSanitizer(func(label string) string { return label }))
+		_, err := interceptor(context.Background(), nil, info, handler)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(fakeCounter.WithArgsForCall(0)).To(gomega.Equal([]string{
+			"service", "testpb.EchoService", "method", "Echo",
+		}))
+	})
+})
@@ -74,6 +74,14 @@ var (
 		LabelNames:   []string{"service", "method"},
 		StatsdFormat: "%{#fqname}.%{service}.%{method}",
 	}
+	streamMaxMessagesReceived = metrics.GaugeOpts{
+		Namespace:    "grpc",
+		Subsystem:    "server",
+		Name:         "stream_max_messages_received",
+		Help:         "The maximum number of messages received in a single stream.",
+		LabelNames:   []string{"service", "method"},
+		StatsdFormat: "%{#fqname}.%{service}.%{method}",
+	}
 )
 
 func NewUnaryMetrics(p metrics.Provider) *UnaryMetrics {
@@ -93,3 +101,25 @@ func NewStreamMetrics(p metrics.Provider) *StreamMetrics {
 		MessagesReceived:  p.NewCounter(streamMessagesReceived),
 	}
 }
+
+// NewMaxMessagesReceivedGauge returns the optional gauge tracking the maximum number
+// of messages received in a single stream. It can be set as StreamMetrics.MaxMessagesReceived.
+func NewMaxMessagesReceivedGauge(p metrics.Provider) metrics.Gauge {
+	return p.NewGauge(streamMaxMessagesReceived)
+}
+
+// NewDefaultMetrics creates the unary and stream metrics of a gRPC server,
+// registered with the standard metric names and label sets. It deliberately leaves out
+// the optional StreamMetrics.MaxMessagesReceived gauge; use Register to create all the metrics.
+func NewDefaultMetrics(p metrics.Provider) (*UnaryMetrics, *StreamMetrics) {
+	return NewUnaryMetrics(p), NewStreamMetrics(p)
+}
+
+// Register creates all the metrics of a gRPC server with the provider, namely the metrics of
+// NewDefaultMetrics and the optional StreamMetrics.MaxMessagesReceived gauge, and returns them
+// ready to be passed to the interceptors.
+func Register(p metrics.Provider) (*UnaryMetrics, *StreamMetrics) {
+	streamMetrics := NewStreamMetrics(p)
+	streamMetrics.MaxMessagesReceived = NewMaxMessagesReceivedGauge(p)
+	return NewUnaryMetrics(p), streamMetrics
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package grpcmetrics_test
+
+import (
+	"github.com/hyperledger/fabric-lib-go/common/metrics"
+	"github.com/hyperledger/fabric-lib-go/common/metrics/metricsfakes"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	"github.com/hyperledger/fabric-x-common/common/grpcmetrics"
+)
+
+var _ = ginkgo.Describe("NewDefaultMetrics", func() {
+	var fakeProvider *metricsfakes.Provider
+
+	ginkgo.BeforeEach(func() {
+		fakeProvider = &metricsfakes.Provider{}
+		fakeProvider.NewHistogramReturns(&metricsfakes.Histogram{})
+		fakeProvider.NewCounterReturns(&metricsfakes.Counter{})
+	})
+
+	ginkgo.It("registers the unary and stream metrics with the standard names", func() {
+		unaryMetrics, streamMetrics := grpcmetrics.NewDefaultMetrics(fakeProvider)
+		gomega.Expect(unaryMetrics.RequestDuration).NotTo(gomega.BeNil())
+		gomega.Expect(unaryMetrics.RequestsReceived).NotTo(gomega.BeNil())
+		gomega.Expect(unaryMetrics.RequestsCompleted).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.RequestDuration).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.RequestsReceived).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.RequestsCompleted).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.MessagesSent).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.MessagesReceived).NotTo(gomega.BeNil())
+
+		gomega.Expect(fakeProvider.NewHistogramCallCount()).To(gomega.Equal(2))
+		histograms := map[string][]string{}
+		for i := range fakeProvider.NewHistogramCallCount() {
+			opts := fakeProvider.NewHistogramArgsForCall(i)
+			gomega.Expect(opts.Namespace).To(gomega.Equal("grpc"))
+			gomega.Expect(opts.Subsystem).To(gomega.Equal("server"))
+			histograms[opts.Name] = opts.LabelNames
+		}
+		gomega.Expect(histograms).To(gomega.Equal(map[string][]string{
+			"unary_request_duration":  {"service", "method", "code"},
+			"stream_request_duration": {"service", "method", "code"},
+		}))
+
+		gomega.Expect(fakeProvider.NewCounterCallCount()).To(gomega.Equal(6))
+		counters := map[string][]string{}
+		for i := range fakeProvider.NewCounterCallCount() {
+			opts := fakeProvider.NewCounterArgsForCall(i)
+			gomega.Expect(opts.Namespace).To(gomega.Equal("grpc"))
+			gomega.Expect(opts.Subsystem).To(gomega.Equal("server"))
+			counters[opts.Name] = opts.LabelNames
+		}
+		gomega.Expect(counters).To(gomega.Equal(map[string][]string{
+			"unary_requests_received":   {"service", "method"},
+			"unary_requests_completed":  {"service", "method", "code"},
+			"stream_requests_received":  {"service", "method"},
+			"stream_requests_completed": {"service", "method", "code"},
+			"stream_messages_received":  {"service", "method"},
+			"stream_messages_sent":      {"service", "method"},
+		}))
+	})
+})
+
+var _ = ginkgo.Describe("Register", func() {
+	var fakeProvider *metricsfakes.Provider
+
+	ginkgo.BeforeEach(func() {
+		fakeProvider = &metricsfakes.Provider{}
+		fakeProvider.NewHistogramReturns(&metricsfakes.Histogram{})
+		fakeProvider.NewCounterReturns(&metricsfakes.Counter{})
+		fakeProvider.NewGaugeReturns(&metricsfakes.Gauge{})
+	})
+
+	ginkgo.It("registers all the metrics, including the optional ones", func() {
+		unaryMetrics, streamMetrics := grpcmetrics.Register(fakeProvider)
+		gomega.Expect(unaryMetrics.RequestDuration).NotTo(gomega.BeNil())
+		gomega.Expect(unaryMetrics.RequestsReceived).NotTo(gomega.BeNil())
+		gomega.Expect(unaryMetrics.RequestsCompleted).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.RequestDuration).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.RequestsReceived).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.RequestsCompleted).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.MessagesSent).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.MessagesReceived).NotTo(gomega.BeNil())
+		gomega.Expect(streamMetrics.MaxMessagesReceived).NotTo(gomega.BeNil())
+
+		var names []string
+		for i := range fakeProvider.NewHistogramCallCount() {
+			names = append(names, fakeProvider.NewHistogramArgsForCall(i).Name)
+		}
+		for i := range fakeProvider.NewCounterCallCount() {
+			names = append(names, fakeProvider.NewCounterArgsForCall(i).Name)
+		}
+		for i := range fakeProvider.NewGaugeCallCount() {
+			names = append(names, fakeProvider.NewGaugeArgsForCall(i).Name)
+		}
+		gomega.Expect(names).To(gomega.ConsistOf(
+			"unary_request_duration",
+			"unary_requests_received",
+			"unary_requests_completed",
+			"stream_request_duration",
+			"stream_requests_received",
+			"stream_requests_completed",
+			"stream_messages_received",
+			"stream_messages_sent",
+			"stream_max_messages_received",
+		))
+	})
+
+	ginkgo.It("registers the metrics of NewDefaultMetrics, along with the optional gauge", func() {
+		defaultProvider := &metricsfakes.Provider{}
+		defaultProvider.NewHistogramReturns(&metricsfakes.Histogram{})
+		defaultProvider.NewCounterReturns(&metricsfakes.Counter{})
+		defaultUnary, defaultStream := grpcmetrics.NewDefaultMetrics(defaultProvider)
+		gomega.Expect(defaultStream.MaxMessagesReceived).To(gomega.BeNil())
+		gomega.Expect(defaultProvider.NewGaugeCallCount()).To(gomega.Equal(0))
+
+		unaryMetrics, streamMetrics := grpcmetrics.Register(fakeProvider)
+		gomega.Expect(unaryMetrics).To(gomega.Equal(defaultUnary))
+		streamMetrics.MaxMessagesReceived = nil
+		gomega.Expect(streamMetrics).To(gomega.Equal(defaultStream))
+
+		var histograms, defaultHistograms []metrics.HistogramOpts
+		for i := range fakeProvider.NewHistogramCallCount() {
+			histograms = append(histograms, fakeProvider.NewHistogramArgsForCall(i))
+		}
+		for i := range defaultProvider.NewHistogramCallCount() {
+			defaultHistograms = append(defaultHistograms, defaultProvider.NewHistogramArgsForCall(i))
+		}
+		gomega.Expect(histograms).To(gomega.ConsistOf(defaultHistograms))
+
+		var counters, defaultCounters []metrics.CounterOpts
+		for i := range fakeProvider.NewCounterCallCount() {
+			counters = append(counters, fakeProvider.NewCounterArgsForCall(i))
+		}
+		for i := range defaultProvider.NewCounterCallCount() {
+			defaultCounters = append(defaultCounters, defaultProvider.NewCounterArgsForCall(i))
+		}
+		gomega.Expect(counters).To(gomega.ConsistOf(defaultCounters))
+	})
+})
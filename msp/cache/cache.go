@@ -51,6 +51,11 @@ type cachedMSP struct {
 	satisfiesPrincipalCache *secondChanceCache
 }
 
+// Unwrap returns the underlying MSP.
+func (c *cachedMSP) Unwrap() msp.MSP {
+	return c.MSP
+}
+
 type cachedIdentity struct {
 	msp.Identity
 	cache *cachedMSP
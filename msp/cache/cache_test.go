@@ -14,8 +14,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/hyperledger/fabric-x-common/api/msppb"
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
 	"github.com/hyperledger/fabric-x-common/msp"
 	"github.com/hyperledger/fabric-x-common/msp/mocks"
 )
@@ -31,6 +33,33 @@ func TestNewCacheMsp(t *testing.T) {
 	require.NotNil(t, i)
 }
 
+func TestUnwrap(t *testing.T) {
+	mockMSP := &mocks.MockMSP{}
+	i, err := New(mockMSP)
+	require.NoError(t, err)
+	require.Same(t, mockMSP, i.(*cachedMSP).Unwrap())
+	require.Nil(t, msp.RootCAs(i))
+}
+
+func TestExportMSPConfig(t *testing.T) {
+	verifyingMSP, err := msp.LoadVerifyingMspDir(msp.DirLoadParameters{MspDir: configtest.GetDevMspDir()})
+	require.NoError(t, err)
+	cached, err := New(verifyingMSP)
+	require.NoError(t, err)
+
+	// The cached MSP exports the config of the underlying MSP.
+	expected, err := msp.ExportMSPConfig(verifyingMSP)
+	require.NoError(t, err)
+	conf, err := msp.ExportMSPConfig(cached)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(expected, conf))
+
+	cached, err = New(&mocks.MockMSP{})
+	require.NoError(t, err)
+	_, err = msp.ExportMSPConfig(cached)
+	require.EqualError(t, err, "MSP of type *mocks.MockMSP does not support exporting its config")
+}
+
 func TestSetup(t *testing.T) {
 	mockMSP := &mocks.MockMSP{}
 	i, err := New(mockMSP)
@@ -148,3 +148,115 @@ func certToPEM(certificate *x509.Certificate) string {
 	}
 	return cert.String()
 }
+
+// ValidateChainPEM validates a certificate chain given as a sequence of
+// concatenated PEM blocks. The certificates may appear in any order: they
+// are ordered from the leaf up to the root, and each certificate is checked
+// to be signed by the next one, up to a self-signed root.
+// If a link is broken, the returned error identifies it.
+func ValidateChainPEM(chainPEM []byte) error {
+	certs, err := parseCertificatesPEM(chainPEM)
+	if err != nil {
+		return err
+	}
+	if len(certs) == 0 {
+		return errors.New("no certificates found in chain")
+	}
+
+	chain, err := orderChain(certs)
+	if err != nil {
+		return err
+	}
+
+	for i, cert := range chain {
+		parent := cert
+		if i+1 < len(chain) {
+			parent = chain[i+1]
+		} else if !isSelfIssued(cert) {
+			return errors.Errorf("link %d: certificate [%s] is not self-signed and its issuer [%s] is missing from the chain",
+				i, cert.Subject, cert.Issuer)
+		}
+		if err := cert.CheckSignatureFrom(parent); err != nil {
+			return errors.Wrapf(err, "link %d: certificate [%s] is not signed by [%s]", i, cert.Subject, parent.Subject)
+		}
+	}
+
+	if len(chain) != len(certs) {
+		return errors.Errorf("chain contains %d certificates not linked to the leaf [%s]",
+			len(certs)-len(chain), chain[0].Subject)
+	}
+
+	return nil
+}
+
+// parseCertificatesPEM parses all the certificates found in the passed PEM bytes.
+func parseCertificatesPEM(raw []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(raw); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed parsing certificate at position %d", len(certs))
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// orderChain sorts the passed certificates from the leaf up to the root,
+// following the issuer of each certificate. The returned chain stops at the
+// first certificate whose issuer is not part of the passed ones.
+func orderChain(certs []*x509.Certificate) ([]*x509.Certificate, error) {
+	// The leaf is a certificate that does not issue any other one.
+	// Self-signed certificates are only taken as leaf when alone.
+	var leaves []*x509.Certificate
+	for _, cert := range certs {
+		if findIssued(cert, certs) == nil && (len(certs) == 1 || !isSelfIssued(cert)) {
+			leaves = append(leaves, cert)
+		}
+	}
+	switch len(leaves) {
+	case 0:
+		return nil, errors.New("chain has no leaf certificate")
+	case 1:
+	default:
+		return nil, errors.Errorf("chain has more than one leaf: [%s] and [%s]", leaves[0].Subject, leaves[1].Subject)
+	}
+
+	chain := []*x509.Certificate{leaves[0]}
+	for current := leaves[0]; !isSelfIssued(current) && len(chain) < len(certs); {
+		current = findIssuer(current, certs)
+		if current == nil {
+			break
+		}
+		chain = append(chain, current)
+	}
+
+	return chain, nil
+}
+
+// findIssued returns a certificate, other than cert, issued by cert, if any.
+func findIssued(cert *x509.Certificate, certs []*x509.Certificate) *x509.Certificate {
+	for _, other := range certs {
+		if other != cert && bytes.Equal(other.RawIssuer, cert.RawSubject) {
+			return other
+		}
+	}
+	return nil
+}
+
+// findIssuer returns the certificate, other than cert, that issued cert, if any.
+func findIssuer(cert *x509.Certificate, certs []*x509.Certificate) *x509.Certificate {
+	for _, other := range certs {
+		if other != cert && bytes.Equal(other.RawSubject, cert.RawIssuer) {
+			return other
+		}
+	}
+	return nil
+}
+
+func isSelfIssued(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject)
+}
@@ -23,6 +23,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/pem"
 	"math/big"
 	"net"
 	"testing"
@@ -184,3 +185,104 @@ func generateSelfSignedCert(t *testing.T, now time.Time) (*ecdsa.PrivateKey, *x5
 
 	return k, cert
 }
+
+func TestValidateChainPEM(t *testing.T) {
+	t.Parallel()
+	root := newTestChainCert(t, "root", nil, true)
+	inter := newTestChainCert(t, "intermediate", root, true)
+	leaf := newTestChainCert(t, "leaf", inter, false)
+
+	t.Run("valid chain", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, ValidateChainPEM(chainToPEM(leaf, inter, root)))
+	})
+
+	t.Run("valid chain in any order", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, ValidateChainPEM(chainToPEM(root, leaf, inter)))
+	})
+
+	t.Run("self-signed certificate only", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, ValidateChainPEM(chainToPEM(root)))
+	})
+
+	t.Run("missing intermediate", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateChainPEM(chainToPEM(leaf, root))
+		require.ErrorContains(t, err,
+			"link 0: certificate [CN=leaf] is not self-signed and its issuer [CN=intermediate] is missing")
+	})
+
+	t.Run("missing root", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateChainPEM(chainToPEM(leaf, inter))
+		require.ErrorContains(t, err,
+			"link 1: certificate [CN=intermediate] is not self-signed and its issuer [CN=root] is missing")
+	})
+
+	t.Run("broken link", func(t *testing.T) {
+		t.Parallel()
+		// An intermediate with the same subject but a different key.
+		otherInter := newTestChainCert(t, "intermediate", root, true)
+		err := ValidateChainPEM(chainToPEM(leaf, otherInter, root))
+		require.ErrorContains(t, err, "link 0: certificate [CN=leaf] is not signed by [CN=intermediate]")
+	})
+
+	t.Run("unrelated certificate", func(t *testing.T) {
+		t.Parallel()
+		other := newTestChainCert(t, "other", nil, true)
+		err := ValidateChainPEM(chainToPEM(leaf, inter, root, other))
+		require.ErrorContains(t, err, "chain contains 1 certificates not linked to the leaf [CN=leaf]")
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		require.EqualError(t, ValidateChainPEM(nil), "no certificates found in chain")
+	})
+}
+
+type testChainCert struct {
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestChainCert(t *testing.T, cn string, issuer *testChainCert, isCA bool) *testChainCert {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	parent, parentKey := template, k
+	if issuer != nil {
+		parent, parentKey = issuer.cert, issuer.key
+	}
+
+	certRaw, err := x509.CreateCertificate(rand.Reader, template, parent, &k.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(certRaw)
+	require.NoError(t, err)
+
+	return &testChainCert{key: k, cert: cert}
+}
+
+func chainToPEM(certs ...*testChainCert) []byte {
+	var res []byte
+	for _, c := range certs {
+		res = append(res, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})...)
+	}
+	return res
+}
@@ -7,9 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/x509"
 	"encoding/pem"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	idemixmsp "github.com/IBM/idemix/msp"
 	"github.com/cockroachdb/errors"
@@ -170,7 +173,6 @@ func GetLocalMspConfigWithType(dir string, bccspConfig *factory.FactoryOpts, ID,
 }
 
 func GetLocalMspConfig(dir string, bccspConfig *factory.FactoryOpts, ID string) (*msp.MSPConfig, error) {
-	signcertDir := filepath.Join(dir, signcerts)
 	keystoreDir := filepath.Join(dir, keystore)
 	bccspConfig = SetupBCCSPKeystoreConfig(bccspConfig, keystoreDir)
 
@@ -179,6 +181,13 @@ func GetLocalMspConfig(dir string, bccspConfig *factory.FactoryOpts, ID string)
 		return nil, errors.WithMessage(err, "could not initialize BCCSP Factories")
 	}
 
+	return getLocalMspConfig(dir, ID)
+}
+
+// getLocalMspConfig returns a local MSP config given a directory and ID, leaving
+// the private key to the BCCSP the MSP is set up with.
+func getLocalMspConfig(dir, ID string) (*msp.MSPConfig, error) {
+	signcertDir := filepath.Join(dir, signcerts)
 	signcert, err := getPemMaterialFromDir(signcertDir)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not load signing certificate from directory %s", signcertDir)
@@ -395,3 +404,139 @@ func loadCertificateAt(dir, certificatePath, ouType string) []byte {
 
 	return nil
 }
+
+// nodeOUIdentifier is an OU identifier of the NodeOUs configuration, with its parsed certificate, if set.
+type nodeOUIdentifier struct {
+	ou   string
+	cert *x509.Certificate
+}
+
+// ValidateNodeOUs validates the NodeOUs configuration (config.yaml) of the MSP at mspDir against its certificates.
+// The certificates referenced by the OU identifiers must exist, and each certificate in signcerts and admincerts
+// must carry the OU of one of the identifiers and, if the identifier references a certificate, chain to it.
+// An MSP without a configuration file, or with NodeOUs disabled, is valid.
+func ValidateNodeOUs(mspDir string) error {
+	configFile := filepath.Join(mspDir, configfilename)
+	raw, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed loading configuration file at [%s]", configFile)
+	}
+	configuration := Configuration{}
+	if err = yaml.Unmarshal(raw, &configuration); err != nil {
+		return errors.Wrapf(err, "failed unmarshalling configuration file at [%s]", configFile)
+	}
+	if configuration.NodeOUs == nil || !configuration.NodeOUs.Enable {
+		return nil
+	}
+
+	identifiers, err := loadNodeOUIdentifiers(mspDir, configuration.NodeOUs)
+	if err != nil {
+		return err
+	}
+	intermediates := x509.NewCertPool()
+	intermediateCerts, err := getPemMaterialFromDir(filepath.Join(mspDir, intermediatecerts))
+	if err != nil && !os.IsNotExist(err) {
+		return errors.WithMessage(err, "failed loading intermediate ca certs")
+	}
+	for _, raw := range intermediateCerts {
+		intermediates.AppendCertsFromPEM(raw)
+	}
+
+	for _, dir := range []string{signcerts, admincerts} {
+		if err = validateNodeOUsOfDir(filepath.Join(mspDir, dir), identifiers, intermediates); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadNodeOUIdentifiers returns the configured OU identifiers, loading the certificates they reference.
+func loadNodeOUIdentifiers(mspDir string, nodeOUs *NodeOUs) ([]*nodeOUIdentifier, error) {
+	var identifiers []*nodeOUIdentifier
+	for _, id := range []struct {
+		role   string
+		config *OrganizationalUnitIdentifiersConfiguration
+	}{
+		{role: "ClientOU", config: nodeOUs.ClientOUIdentifier},
+		{role: "PeerOU", config: nodeOUs.PeerOUIdentifier},
+		{role: "AdminOU", config: nodeOUs.AdminOUIdentifier},
+		{role: "OrdererOU", config: nodeOUs.OrdererOUIdentifier},
+	} {
+		if id.config == nil || id.config.OrganizationalUnitIdentifier == "" {
+			continue
+		}
+		identifier := &nodeOUIdentifier{ou: id.config.OrganizationalUnitIdentifier}
+		if id.config.Certificate != "" {
+			f := filepath.Join(mspDir, id.config.Certificate)
+			raw, err := readPemFile(f)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "invalid %s identifier certificate", id.role)
+			}
+			certs, err := parseCertificatesPEM(raw)
+			if err != nil || len(certs) == 0 {
+				return nil, errors.Errorf("invalid %s identifier certificate at [%s]: no valid certificate found", id.role, f)
+			}
+			identifier.cert = certs[0]
+		}
+		identifiers = append(identifiers, identifier)
+	}
+	if len(identifiers) == 0 {
+		return nil, errors.New("NodeOUs are enabled but no OU identifier is configured")
+	}
+	return identifiers, nil
+}
+
+// validateNodeOUsOfDir checks that each certificate in dir matches one of the OU identifiers.
+func validateNodeOUsOfDir(dir string, identifiers []*nodeOUIdentifier, intermediates *x509.CertPool) error {
+	pemCerts, err := getPemMaterialFromDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "failed loading certificates at [%s]", dir)
+	}
+	for _, raw := range pemCerts {
+		certs, err := parseCertificatesPEM(raw)
+		if err != nil {
+			return errors.WithMessagef(err, "failed parsing certificate at [%s]", dir)
+		}
+		for _, cert := range certs {
+			if !matchesNodeOUIdentifier(cert, identifiers, intermediates) {
+				return errors.Errorf("certificate [%s] at [%s] with OUs %v does not match any NodeOU identifier",
+					cert.Subject, dir, cert.Subject.OrganizationalUnit)
+			}
+		}
+	}
+	return nil
+}
+
+// matchesNodeOUIdentifier returns true if the certificate carries the OU of one of the identifiers
+// and chains to the identifier's certificate, if set.
+func matchesNodeOUIdentifier(
+	cert *x509.Certificate, identifiers []*nodeOUIdentifier, intermediates *x509.CertPool,
+) bool {
+	for _, id := range identifiers {
+		if !slices.Contains(cert.Subject.OrganizationalUnit, id.ou) {
+			continue
+		}
+		if id.cert == nil {
+			return true
+		}
+		roots := x509.NewCertPool()
+		roots.AddCert(id.cert)
+		_, err := cert.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+			// Validate the chain independently of the local current time, as the MSP does.
+			CurrentTime: cert.NotBefore.Add(time.Second),
+		})
+		if err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger/fabric-x-common/api/msppb"
+)
+
+func TestDeserializeIdentities(t *testing.T) {
+	t.Parallel()
+	signer, err := localMsp.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	valid, err := signer.Serialize()
+	require.NoError(t, err)
+	otherOrg, err := proto.Marshal(msppb.NewIdentity("OtherOrg", []byte("not a cert")))
+	require.NoError(t, err)
+
+	serialized := [][]byte{valid, []byte("garbage"), otherOrg, valid}
+	ids, errs := DeserializeIdentities(localMsp, serialized)
+	require.Len(t, ids, len(serialized))
+	require.Len(t, errs, len(serialized))
+
+	require.NoError(t, errs[0])
+	require.Equal(t, signer.GetIdentifier(), ids[0].GetIdentifier())
+	require.ErrorContains(t, errs[1], "could not deserialize a SerializedIdentity")
+	require.Nil(t, ids[1])
+	require.EqualError(t, errs[2], "expected MSP ID SampleOrg, received OtherOrg")
+	require.Nil(t, ids[2])
+	require.NoError(t, errs[3])
+	require.Same(t, ids[0], ids[3])
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-lib-go/bccsp/sw"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestExportMSPConfig(t *testing.T) {
+	t.Parallel()
+	thisMSP := getLocalMSP(t, configtest.GetDevMspDir())
+	signer, err := thisMSP.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	serialized, err := signer.Serialize()
+	require.NoError(t, err)
+
+	conf, err := ExportMSPConfig(thisMSP)
+	require.NoError(t, err)
+	require.Equal(t, int32(FABRIC), conf.Type)
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	reloaded, err := NewBccspMspWithKeyStore(MSPv1_0, sw.NewDummyKeyStore(), cryptoProvider)
+	require.NoError(t, err)
+	require.NoError(t, reloaded.Setup(conf))
+
+	id, err := reloaded.GetIdentifier()
+	require.NoError(t, err)
+	require.Equal(t, "SampleOrg", id)
+	require.Equal(t, thisMSP.GetTLSRootCerts(), reloaded.GetTLSRootCerts())
+
+	// The signing identity is not exported, but it is validated by the reloaded MSP as by the original one.
+	_, err = reloaded.GetDefaultSigningIdentity()
+	require.Error(t, err)
+	deserialized, err := deserializeAndValidate(reloaded, serialized)
+	require.NoError(t, err)
+	require.NoError(t, thisMSP.Validate(deserialized))
+
+	// The export of the reloaded MSP is the same as the original one.
+	reexported, err := ExportMSPConfig(reloaded)
+	require.NoError(t, err)
+	require.Equal(t, conf.Config, reexported.Config)
+}
+
+func TestExportMSPConfigNotSetUp(t *testing.T) {
+	t.Parallel()
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	thisMSP, err := NewBccspMspWithKeyStore(MSPv1_0, sw.NewDummyKeyStore(), cryptoProvider)
+	require.NoError(t, err)
+
+	_, err = ExportMSPConfig(thisMSP)
+	require.EqualError(t, err, "the MSP has not been set up")
+}
@@ -98,48 +98,88 @@ type DirLoadParameters struct {
 	MspDir  string
 	MspName string
 	CspConf *factory.FactoryOpts
+	// BCCSP is the crypto provider the MSP uses for key operations, e.g., an HSM-backed one.
+	// When set, the provider is used as is, and CspConf must not be set.
+	BCCSP bccsp.BCCSP
+	// PermittedOUs, when not empty, restricts the OUs the identities of the MSP may have:
+	// an identity with an OU outside the list fails validation.
+	PermittedOUs []string
 }
 
 // LoadLocalMspDir loads an MSP directory.
 //
 //nolint:ireturn,nolintlint // method may return any MSP implementation.
 func LoadLocalMspDir(p DirLoadParameters) (MSP, error) {
+	if err := validateParameters(p); err != nil {
+		return nil, err
+	}
 	p = defaultParameters(p)
-	conf, err := GetLocalMspConfig(p.MspDir, p.CspConf, p.MspName)
+	var conf *msppb.MSPConfig
+	var err error
+	if p.BCCSP != nil {
+		// The provider holds the signing key, so the BCCSP factories need not be initialized.
+		conf, err = getLocalMspConfig(p.MspDir, p.MspName)
+	} else {
+		conf, err = GetLocalMspConfig(p.MspDir, p.CspConf, p.MspName)
+	}
 	if err != nil {
 		return nil, errors.Wrapf(err, "error loading local MSP configuration [%s]", p.MspDir)
 	}
-	return loadMSP(p.CspConf, conf)
+	return loadMSP(p, conf)
 }
 
 // LoadVerifyingMspDir loads an MSP directory.
 //
 //nolint:ireturn,nolintlint // method may return any MSP implementation.
 func LoadVerifyingMspDir(p DirLoadParameters) (MSP, error) {
+	if err := validateParameters(p); err != nil {
+		return nil, err
+	}
 	p = defaultParameters(p)
 	conf, err := GetVerifyingMspConfig(p.MspDir, p.MspName, ProviderTypeToString(FABRIC))
 	if err != nil {
 		return nil, errors.Wrapf(err, "error loading verifing MSP configuration [%s]", p.MspDir)
 	}
-	return loadMSP(p.CspConf, conf)
+	return loadMSP(p, conf)
 }
 
 //nolint:ireturn,nolintlint // method may return any MSP implementation.
-func loadMSP(cspConfig *factory.FactoryOpts, conf *msppb.MSPConfig) (MSP, error) {
-	csp, err := factory.GetBCCSPFromOpts(cspConfig)
-	if err != nil {
-		return nil, errors.Wrapf(err, "error getting BCCSP from config")
+func loadMSP(p DirLoadParameters, conf *msppb.MSPConfig) (MSP, error) {
+	csp := p.BCCSP
+	if csp == nil {
+		var err error
+		csp, err = factory.GetBCCSPFromOpts(p.CspConf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error getting BCCSP from config")
+		}
 	}
 	mspInst, err := New(Options[ProviderTypeToString(FABRIC)], csp)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error creating MSP instance")
 	}
+	if len(p.PermittedOUs) > 0 {
+		bccspMSP, ok := mspInst.(*bccspmsp)
+		if !ok {
+			return nil, errors.New("permitted OUs are only supported by X.509 MSPs")
+		}
+		bccspMSP.permittedOUs = make(map[string]struct{}, len(p.PermittedOUs))
+		for _, ou := range p.PermittedOUs {
+			bccspMSP.permittedOUs[ou] = struct{}{}
+		}
+	}
 	err = mspInst.Setup(conf)
 	return mspInst, errors.Wrapf(err, "error setting up MSP instance")
 }
 
+func validateParameters(p DirLoadParameters) error {
+	if p.BCCSP != nil && p.CspConf != nil {
+		return errors.New("only one of BCCSP and CspConf may be set")
+	}
+	return nil
+}
+
 func defaultParameters(p DirLoadParameters) DirLoadParameters {
-	if p.CspConf == nil {
+	if p.CspConf == nil && p.BCCSP == nil {
 		p.CspConf = factory.GetDefaultOpts()
 	}
 	if p.MspName == "" {
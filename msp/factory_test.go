@@ -7,12 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"testing"
 
+	"github.com/hyperledger/fabric-lib-go/bccsp/factory"
 	"github.com/hyperledger/fabric-lib-go/bccsp/sw"
 	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/api/msppb"
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
 )
 
 func TestNewInvalidOpts(t *testing.T) {
@@ -74,3 +80,91 @@ func TestNew(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, i)
 }
+
+func TestLoadLocalMspDirWithBCCSP(t *testing.T) {
+	t.Parallel()
+	mspDir := configtest.GetDevMspDir()
+	ks, err := sw.NewFileBasedKeyStore(nil, filepath.Join(mspDir, "keystore"), true)
+	require.NoError(t, err)
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(ks)
+	require.NoError(t, err)
+
+	t.Run("signs with the given provider", func(t *testing.T) {
+		t.Parallel()
+		m, err := LoadLocalMspDir(DirLoadParameters{
+			MspDir:  mspDir,
+			MspName: "SampleOrg",
+			BCCSP:   cryptoProvider,
+		})
+		require.NoError(t, err)
+
+		signer, err := m.GetDefaultSigningIdentity()
+		require.NoError(t, err)
+		msg := []byte("hello")
+		sig, err := signer.Sign(msg)
+		require.NoError(t, err)
+		require.NoError(t, signer.Verify(msg, sig))
+	})
+
+	t.Run("rejects both BCCSP and CspConf", func(t *testing.T) {
+		t.Parallel()
+		_, err := LoadLocalMspDir(DirLoadParameters{
+			MspDir:  mspDir,
+			CspConf: factory.GetDefaultOpts(),
+			BCCSP:   cryptoProvider,
+		})
+		require.EqualError(t, err, "only one of BCCSP and CspConf may be set")
+	})
+}
+
+func TestLoadMspDirWithPermittedOUs(t *testing.T) {
+	t.Parallel()
+	// The admin of this MSP is a client, and its signer a peer.
+	mspDir := filepath.Join("testdata", "nodeous3")
+	peerCert, err := os.ReadFile(filepath.Join(mspDir, "signcerts", "peer0-cert.pem"))
+	require.NoError(t, err)
+	peer := msppb.NewIdentity("SampleOrg", peerCert)
+
+	for _, tc := range []struct {
+		name         string
+		permittedOUs []string
+		expectedErr  string
+	}{
+		{name: "no restriction"},
+		{name: "permitted", permittedOUs: []string{"OU_client", "OU_peer"}},
+		{
+			name:         "not permitted",
+			permittedOUs: []string{"OU_client"},
+			expectedErr:  "could not validate identity's OUs: the identity's organizational unit OU_peer is not permitted in MSP SampleOrg",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			m, err := LoadVerifyingMspDir(DirLoadParameters{
+				MspDir:       mspDir,
+				MspName:      "SampleOrg",
+				PermittedOUs: tc.permittedOUs,
+			})
+			require.NoError(t, err)
+			id, err := m.DeserializeIdentity(peer)
+			require.NoError(t, err)
+
+			err = m.Validate(id)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+
+	t.Run("rejects an MSP whose admins are not permitted", func(t *testing.T) {
+		t.Parallel()
+		_, err := LoadVerifyingMspDir(DirLoadParameters{
+			MspDir:       configtest.GetDevMspDir(),
+			MspName:      "SampleOrg",
+			PermittedOUs: []string{"client"},
+		})
+		require.ErrorContains(t, err, "the identity's organizational unit COP is not permitted in MSP SampleOrg")
+	})
+}
@@ -9,6 +9,7 @@ package msp
 import (
 	"crypto"
 	"crypto/rand"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/pem"
@@ -175,6 +176,180 @@ func NewSerializedIdentityWithIDOfCert(mspID, certID string) ([]byte, error) {
 	return raw, nil
 }
 
+// ComputeIdentityIdentifier returns the MSP ID and the identity identifier of the passed
+// serialized identity, without the need of an MSP instance.
+// The identifier is the hex encoded SHA-256 hash of the identity's certificate, matching
+// the identifier computed by MSPs using the default identity identifier hash function.
+// Certificates are assumed to be already sanitized, namely their signature is in low-S form.
+// If the serialized identity carries a certificate ID instead of a certificate, that ID is returned.
+func ComputeIdentityIdentifier(serializedIdentity []byte) (mspID, id string, err error) {
+	sID := &msppb.Identity{}
+	if err := proto.Unmarshal(serializedIdentity, sID); err != nil {
+		return "", "", errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+
+	switch sID.Creator.(type) {
+	case *msppb.Identity_Certificate:
+		bl, _ := pem.Decode(sID.GetCertificate())
+		if bl == nil {
+			return "", "", errors.New("could not decode the PEM structure")
+		}
+		cert, err := x509.ParseCertificate(bl.Bytes)
+		if err != nil {
+			return "", "", errors.Wrap(err, "parseCertificate failed")
+		}
+		digest := sha256.Sum256(cert.Raw)
+		return sID.MspId, hex.EncodeToString(digest[:]), nil
+	case *msppb.Identity_CertificateId:
+		return sID.MspId, sID.GetCertificateId(), nil
+	default:
+		return "", "", errors.New("unknown creator type in the identity")
+	}
+}
+
+// SameOrg reports whether two serialized identities belong to the same organization, namely whether
+// they carry the same MSP ID. The identities are neither deserialized with an MSP nor validated.
+func SameOrg(a, b []byte) (bool, error) {
+	mspIDA, err := serializedMSPID(a)
+	if err != nil {
+		return false, errors.WithMessage(err, "invalid first identity")
+	}
+	mspIDB, err := serializedMSPID(b)
+	if err != nil {
+		return false, errors.WithMessage(err, "invalid second identity")
+	}
+	return mspIDA == mspIDB, nil
+}
+
+func serializedMSPID(serializedIdentity []byte) (string, error) {
+	sID := &msppb.Identity{}
+	if err := proto.Unmarshal(serializedIdentity, sID); err != nil {
+		return "", errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+	if sID.MspId == "" {
+		return "", errors.New("the identity has no MSP ID")
+	}
+	return sID.MspId, nil
+}
+
+// EnrollmentID returns the common name of the certificate of the passed serialized identity, which is
+// commonly the enrollment ID of the identity with its CA. It is meant for audit logging: the identity is
+// neither deserialized with an MSP nor validated. The certificate may be encoded either in PEM or in DER,
+// as done by SerializeMinimal. It fails if the certificate has no common name.
+func EnrollmentID(serialized []byte) (string, error) {
+	sID := &msppb.Identity{}
+	if err := proto.Unmarshal(serialized, sID); err != nil {
+		return "", errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+	raw := sID.GetCertificate()
+	if len(raw) == 0 {
+		return "", errors.New("the identity has no certificate")
+	}
+	if bl, _ := pem.Decode(raw); bl != nil {
+		raw = bl.Bytes
+	}
+	cert, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return "", errors.Wrap(err, "parseCertificate failed")
+	}
+	if cert.Subject.CommonName == "" {
+		return "", errors.Errorf("the certificate of the identity has no common name [%s]", cert.Subject)
+	}
+	return cert.Subject.CommonName, nil
+}
+
+// DeserializeIdentities deserializes and validates a batch of serialized identities with the given MSP.
+// The i-th returned identity and error correspond to the i-th serialized identity; the identity is nil
+// if its error is not. Repeated identities in the batch are deserialized once, and the MSP's own
+// identity cache, if any, is used for the rest.
+func DeserializeIdentities(m MSP, serialized [][]byte) ([]Identity, []error) {
+	ids := make([]Identity, len(serialized))
+	errs := make([]error, len(serialized))
+	seen := make(map[string]int, len(serialized))
+	for i, raw := range serialized {
+		if j, ok := seen[string(raw)]; ok {
+			ids[i], errs[i] = ids[j], errs[j]
+			continue
+		}
+		seen[string(raw)] = i
+		ids[i], errs[i] = deserializeAndValidate(m, raw)
+	}
+	return ids, errs
+}
+
+// ValidateIdentityAtTime deserializes and validates a serialized identity with the given MSP, and checks
+// that its certificate is within its validity period at the given time.
+func ValidateIdentityAtTime(m MSP, serialized []byte, t time.Time) error {
+	id, err := deserializeAndValidate(m, serialized)
+	if err != nil {
+		return err
+	}
+	x509ID, ok := id.(*identity)
+	if !ok {
+		return errors.Errorf("identity of type %T has no certificate", id)
+	}
+	if t.Before(x509ID.cert.NotBefore) {
+		return errors.Errorf("identity is not valid before %s", x509ID.cert.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if t.After(x509ID.cert.NotAfter) {
+		return errors.Errorf("identity expired at %s", x509ID.cert.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// SerializeMinimal serializes a signing identity with its certificate in DER, rather than in PEM, which
+// saves the PEM armor and the base64 overhead. The certificate itself is kept intact, as any change to
+// its extensions would void the CA signature that validating the identity relies on.
+// Use DeserializeMinimal to deserialize the result.
+func SerializeMinimal(si SigningIdentity) ([]byte, error) {
+	serialized, err := si.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	sID := &msppb.Identity{}
+	if err = proto.Unmarshal(serialized, sID); err != nil {
+		return nil, errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+	bl, _ := pem.Decode(sID.GetCertificate())
+	if bl == nil {
+		return nil, errors.New("could not decode the PEM structure")
+	}
+	return marshalMessage(msppb.NewIdentity(sID.MspId, bl.Bytes), "failed serializing minimal identity")
+}
+
+// DeserializeMinimal deserializes and validates with the given MSP an identity serialized by SerializeMinimal.
+func DeserializeMinimal(m MSP, serialized []byte) (Identity, error) { //nolint:ireturn
+	sID := &msppb.Identity{}
+	if err := proto.Unmarshal(serialized, sID); err != nil {
+		return nil, errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+	der := sID.GetCertificate()
+	if len(der) == 0 {
+		return nil, errors.New("the identity has no certificate")
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	raw, err := NewSerializedIdentity(sID.MspId, certPEM)
+	if err != nil {
+		return nil, err
+	}
+	return deserializeAndValidate(m, raw)
+}
+
+func deserializeAndValidate(m MSP, serialized []byte) (Identity, error) { //nolint:ireturn
+	sID := &msppb.Identity{}
+	if err := proto.Unmarshal(serialized, sID); err != nil {
+		return nil, errors.Wrap(err, "could not deserialize a SerializedIdentity")
+	}
+	id, err := m.DeserializeIdentity(sID)
+	if err != nil {
+		return nil, err
+	}
+	if err = id.Validate(); err != nil {
+		return nil, errors.WithMessage(err, "identity is not valid")
+	}
+	return id, nil
+}
+
 // Verify checks against a signature and a message
 // to determine whether this identity produced the
 // signature; it returns nil if so or an error otherwise
@@ -21,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric-lib-go/bccsp/utils"
 	"github.com/hyperledger/fabric-protos-go-apiv2/msp"
 	"github.com/onsi/gomega"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/hyperledger/fabric-x-common/api/msppb"
@@ -214,3 +215,97 @@ func TestIdentityValidation(t *testing.T) {
 		gt.Expect(err).NotTo(gomega.HaveOccurred())
 	})
 }
+
+func TestSameOrg(t *testing.T) {
+	t.Parallel()
+	id, err := localMsp.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	serialized, err := id.Serialize()
+	require.NoError(t, err)
+	sameMSP, err := NewSerializedIdentityWithIDOfCert(id.GetMSPIdentifier(), "another-cert")
+	require.NoError(t, err)
+	otherMSP, err := NewSerializedIdentity("OtherOrg", []byte("cert"))
+	require.NoError(t, err)
+
+	same, err := SameOrg(serialized, sameMSP)
+	require.NoError(t, err)
+	require.True(t, same)
+
+	same, err = SameOrg(serialized, otherMSP)
+	require.NoError(t, err)
+	require.False(t, same)
+
+	_, err = SameOrg([]byte("garbage"), serialized)
+	require.ErrorContains(t, err, "invalid first identity: could not deserialize a SerializedIdentity")
+	noMSPID, err := NewSerializedIdentity("", []byte("cert"))
+	require.NoError(t, err)
+	_, err = SameOrg(serialized, noMSPID)
+	require.EqualError(t, err, "invalid second identity: the identity has no MSP ID")
+}
+
+func TestEnrollmentID(t *testing.T) {
+	t.Parallel()
+	id, err := localMsp.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+
+	serialized, err := id.Serialize()
+	require.NoError(t, err)
+	enrollmentID, err := EnrollmentID(serialized)
+	require.NoError(t, err)
+	require.Equal(t, "peer0.org1.example.com", enrollmentID)
+
+	// The minimal serialization, with the certificate in DER, yields the same enrollment ID.
+	minimal, err := SerializeMinimal(id)
+	require.NoError(t, err)
+	enrollmentID, err = EnrollmentID(minimal)
+	require.NoError(t, err)
+	require.Equal(t, "peer0.org1.example.com", enrollmentID)
+
+	noCN, err := NewSerializedIdentity("SampleOrg", []byte(caCertPem))
+	require.NoError(t, err)
+	_, err = EnrollmentID(noCN)
+	require.EqualError(t, err, "the certificate of the identity has no common name [O=CA,ST=Santa Catarina,C=BR]")
+
+	withIDOfCert, err := id.SerializeWithIDOfCert()
+	require.NoError(t, err)
+	_, err = EnrollmentID(withIDOfCert)
+	require.EqualError(t, err, "the identity has no certificate")
+
+	_, err = EnrollmentID([]byte("garbage"))
+	require.ErrorContains(t, err, "could not deserialize a SerializedIdentity")
+}
+
+func TestComputeIdentityIdentifier(t *testing.T) {
+	t.Parallel()
+	id, err := localMsp.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+
+	serialized, err := id.Serialize()
+	require.NoError(t, err)
+	mspID, idID, err := ComputeIdentityIdentifier(serialized)
+	require.NoError(t, err)
+	require.Equal(t, id.GetIdentifier().Mspid, mspID)
+	require.Equal(t, id.GetIdentifier().Id, idID)
+
+	// The serialization carrying the certificate ID yields the same identifier.
+	serializedWithID, err := id.SerializeWithIDOfCert()
+	require.NoError(t, err)
+	require.NotEqual(t, serialized, serializedWithID)
+	mspID2, idID2, err := ComputeIdentityIdentifier(serializedWithID)
+	require.NoError(t, err)
+	require.Equal(t, mspID, mspID2)
+	require.Equal(t, idID, idID2)
+
+	_, _, err = ComputeIdentityIdentifier([]byte("garbage"))
+	require.ErrorContains(t, err, "could not deserialize a SerializedIdentity")
+
+	noPEM, err := NewSerializedIdentity("SampleOrg", []byte("not a PEM"))
+	require.NoError(t, err)
+	_, _, err = ComputeIdentityIdentifier(noPEM)
+	require.EqualError(t, err, "could not decode the PEM structure")
+
+	noCreator, err := proto.Marshal(&msppb.Identity{MspId: "SampleOrg"})
+	require.NoError(t, err)
+	_, _, err = ComputeIdentityIdentifier(noCreator)
+	require.EqualError(t, err, "unknown creator type in the identity")
+}
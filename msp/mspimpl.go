@@ -15,6 +15,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/hyperledger/fabric-lib-go/bccsp"
@@ -113,6 +114,12 @@ type bccspmsp struct {
 	// These are the OUIdentifiers of the clients, peers, admins and orderers.
 	// They are used to tell apart these entities
 	clientOU, peerOU, adminOU, ordererOU *OUIdentifier
+
+	// config is the configuration this MSP was set up with
+	config *msppb.FabricMSPConfig
+
+	// permittedOUs, when not nil, are the only OUs the identities of this MSP may have
+	permittedOUs map[string]struct{}
 }
 
 // newBccspMsp returns an MSP instance backed up by a BCCSP
@@ -279,7 +286,11 @@ func (msp *bccspmsp) Setup(conf1 *m.MSPConfig) error {
 	mspLogger.Debugf("Setting up MSP instance %s", msp.name)
 
 	// setup
-	return msp.internalSetupFunc(conf)
+	if err = msp.internalSetupFunc(conf); err != nil {
+		return err
+	}
+	msp.config = conf
+	return nil
 }
 
 //nolint:ireturn //Identity is an interface.
@@ -312,6 +323,91 @@ func (msp *bccspmsp) GetTLSIntermediateCerts() [][]byte {
 	return msp.tlsIntermediateCerts
 }
 
+// RootCAs returns the root CA certificates the MSP validates identities against,
+// in the order they appear in its configuration. An identity is valid if it chains
+// to any of them, which lets an MSP trust both the old and the new root while its
+// CA is being rotated. It returns nil for MSPs that are not X.509 based.
+func RootCAs(m MSP) []*x509.Certificate {
+	for {
+		switch impl := m.(type) {
+		case *bccspmsp:
+			certs := make([]*x509.Certificate, 0, len(impl.rootCerts))
+			for _, id := range impl.rootCerts {
+				certs = append(certs, id.(*identity).cert)
+			}
+			return certs
+		case interface{ Unwrap() MSP }:
+			m = impl.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// SigningIdentityExpiry returns the time at which the certificate of the MSP's
+// default signing identity expires.
+func SigningIdentityExpiry(m MSP) (time.Time, error) {
+	id, err := m.GetDefaultSigningIdentity()
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to get the default signing identity")
+	}
+	expiry := id.ExpiresAt()
+	if expiry.IsZero() {
+		return time.Time{}, errors.New("the default signing identity has no expiry")
+	}
+	return expiry, nil
+}
+
+// ExportMSPConfig returns the config of a loaded MSP, carrying its trust material.
+// The signing identity is not exported, so setting up an MSP with the returned
+// config yields a verifying MSP that validates the same identities.
+// Wrapping MSPs, such as the cached MSPs of the channels, are unwrapped to reach the exported one.
+func ExportMSPConfig(instance MSP) (*m.MSPConfig, error) {
+	unwrapped := instance
+	for {
+		w, ok := unwrapped.(interface{ Unwrap() MSP })
+		if !ok {
+			break
+		}
+		unwrapped = w.Unwrap()
+	}
+	bmsp, ok := unwrapped.(*bccspmsp)
+	if !ok {
+		return nil, errors.Newf("MSP of type %T does not support exporting its config", unwrapped)
+	}
+	if bmsp.config == nil {
+		return nil, errors.New("the MSP has not been set up")
+	}
+	conf := proto.CloneOf(bmsp.config)
+	conf.SigningIdentity = nil
+	confBytes, err := proto.Marshal(conf)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the fabric MSP config")
+	}
+	return &m.MSPConfig{Type: int32(FABRIC), Config: confBytes}, nil
+}
+
+// SatisfiesPrincipal checks whether the identity satisfies a ROLE, ORGANIZATION_UNIT or IDENTITY
+// principal, as evaluated by the given MSP. It returns nil if it does, or an error explaining why not.
+// Signing identities are evaluated by their public version.
+func SatisfiesPrincipal(instance MSP, id Identity, principal *m.MSPPrincipal) error {
+	if id == nil {
+		return errors.New("identity must not be nil")
+	}
+	if signingID, ok := id.(SigningIdentity); ok {
+		id = signingID.GetPublicVersion()
+	}
+	if principal == nil {
+		return errors.New("principal must not be nil")
+	}
+	switch principal.PrincipalClassification {
+	case m.MSPPrincipal_ROLE, m.MSPPrincipal_ORGANIZATION_UNIT, m.MSPPrincipal_IDENTITY:
+		return instance.SatisfiesPrincipal(id, principal)
+	default:
+		return errors.Newf("unsupported principal classification %s", principal.PrincipalClassification)
+	}
+}
+
 // GetDefaultSigningIdentity returns the
 // default signing identity for this MSP (if any)
 func (msp *bccspmsp) GetDefaultSigningIdentity() (SigningIdentity, error) {
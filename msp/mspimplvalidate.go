@@ -51,6 +51,9 @@ func (msp *bccspmsp) validateIdentity(id *identity) error {
 	}
 
 	err = msp.internalValidateIdentityOusFunc(id)
+	if err == nil {
+		err = msp.validatePermittedOUs(id)
+	}
 	if err != nil {
 		id.validationErr = errors.WithMessage(err, "could not validate identity's OUs")
 		mspLogger.Warnf("Could not validate identity: %s (certificate subject=%s issuer=%s serialnumber=%d)", id.validationErr, id.cert.Subject, id.cert.Issuer, id.cert.SerialNumber)
@@ -231,6 +234,20 @@ func (msp *bccspmsp) validateIdentityOUsV11(id *identity) error {
 	return nil
 }
 
+// validatePermittedOUs checks that all the OUs of the identity are permitted, if this MSP restricts them.
+func (msp *bccspmsp) validatePermittedOUs(id *identity) error {
+	if msp.permittedOUs == nil {
+		return nil
+	}
+	for _, OU := range id.GetOrganizationalUnits() {
+		if _, ok := msp.permittedOUs[OU.OrganizationalUnitIdentifier]; !ok {
+			return errors.Errorf("the identity's organizational unit %s is not permitted in MSP %s",
+				OU.OrganizationalUnitIdentifier, msp.name)
+		}
+	}
+	return nil
+}
+
 func (msp *bccspmsp) validateIdentityOUsV142(id *identity) error {
 	// Run the same checks as per V1
 	err := msp.validateIdentityOUsV1(id)
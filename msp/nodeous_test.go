@@ -17,12 +17,14 @@ limitations under the License.
 package msp
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/hyperledger/fabric-lib-go/bccsp/sw"
 	"github.com/hyperledger/fabric-protos-go-apiv2/msp"
 	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -439,3 +441,58 @@ func TestValidMSPWithNodeOUMissingClassification(t *testing.T) {
 	_, err = getLocalMSPWithVersionAndError(t, "testdata/nodeousbadconf2", MSPv1_4_3)
 	require.NoError(t, err)
 }
+
+func TestValidateNodeOUs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, ValidateNodeOUs("testdata/nodeouorderer"))
+	})
+
+	t.Run("NodeOUs not configured", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, ValidateNodeOUs("testdata/badadmin"))
+	})
+
+	t.Run("missing identifier certificate", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateNodeOUs("testdata/nodeouadmin")
+		require.ErrorContains(t, err, "invalid OrdererOU identifier certificate")
+	})
+
+	t.Run("tampered OU identifier", func(t *testing.T) {
+		t.Parallel()
+		mspDir := t.TempDir()
+		require.NoError(t, os.CopyFS(mspDir, os.DirFS("testdata/nodeouorderer")))
+		tamperNodeOUsConfig(t, mspDir, func(nodeOUs *NodeOUs) {
+			nodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier = "consenter"
+		})
+		err := ValidateNodeOUs(mspDir)
+		require.ErrorContains(t, err, "with OUs [orderer] does not match any NodeOU identifier")
+	})
+
+	t.Run("tampered identifier certificate", func(t *testing.T) {
+		t.Parallel()
+		mspDir := t.TempDir()
+		require.NoError(t, os.CopyFS(mspDir, os.DirFS("testdata/nodeouorderer")))
+		tamperNodeOUsConfig(t, mspDir, func(nodeOUs *NodeOUs) {
+			nodeOUs.OrdererOUIdentifier.Certificate = "cacerts/missing-cert.pem"
+		})
+		err := ValidateNodeOUs(mspDir)
+		require.ErrorContains(t, err, "invalid OrdererOU identifier certificate")
+	})
+}
+
+func tamperNodeOUsConfig(t *testing.T, mspDir string, tamper func(*NodeOUs)) {
+	t.Helper()
+	configFile := filepath.Join(mspDir, configfilename)
+	raw, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	configuration := Configuration{}
+	require.NoError(t, yaml.Unmarshal(raw, &configuration))
+	tamper(configuration.NodeOUs)
+	raw, err = yaml.Marshal(&configuration)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(configFile, raw, 0o600))
+}
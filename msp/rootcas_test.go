@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-lib-go/bccsp/sw"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/api/msppb"
+)
+
+func TestRootCAsMultipleRoots(t *testing.T) {
+	t.Parallel()
+	// The MSP trusts the roots of two unrelated CAs, as it would while rotating its CA.
+	rootDirs := []string{"testdata/mspid", "testdata/nodeous1"}
+	mspDir := t.TempDir()
+	caCertsDir := filepath.Join(mspDir, cacerts)
+	require.NoError(t, os.MkdirAll(caCertsDir, 0o750))
+	var expectedRoots []*x509.Certificate
+	for i, dir := range rootDirs {
+		pems, err := getPemMaterialFromDir(filepath.Join(dir, cacerts))
+		require.NoError(t, err)
+		require.Len(t, pems, 1)
+		require.NoError(t, os.WriteFile(filepath.Join(caCertsDir, []string{"a.pem", "b.pem"}[i]), pems[0], 0o600))
+		expectedRoots = append(expectedRoots, parseTestCert(t, pems[0]))
+	}
+
+	conf, err := GetVerifyingMspConfig(mspDir, "SampleOrg", ProviderTypeToString(FABRIC))
+	require.NoError(t, err)
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	thisMSP, err := NewBccspMspWithKeyStore(MSPv1_0, sw.NewDummyKeyStore(), cryptoProvider)
+	require.NoError(t, err)
+	require.NoError(t, thisMSP.Setup(conf))
+
+	// Wrapping MSPs are unwrapped to reach the roots.
+	for _, m := range []MSP{thisMSP, &wrappedMSP{MSP: thisMSP}} {
+		roots := RootCAs(m)
+		require.Len(t, roots, len(expectedRoots))
+		// The roots are sanitized on setup, so they are matched by subject and key.
+		for i := range roots {
+			require.Equal(t, expectedRoots[i].RawSubject, roots[i].RawSubject)
+			require.Equal(t, expectedRoots[i].RawSubjectPublicKeyInfo, roots[i].RawSubjectPublicKeyInfo)
+		}
+
+		// Identities issued by either root are valid.
+		for _, dir := range rootDirs {
+			pems, err := getPemMaterialFromDir(filepath.Join(dir, signcerts))
+			require.NoError(t, err)
+			id, err := m.DeserializeIdentity(msppb.NewIdentity("SampleOrg", pems[0]))
+			require.NoError(t, err)
+			require.NoError(t, m.Validate(id), dir)
+		}
+	}
+}
+
+func TestRootCAsNonX509(t *testing.T) {
+	t.Parallel()
+	require.Nil(t, RootCAs(nil))
+}
+
+type wrappedMSP struct {
+	MSP
+}
+
+func (w *wrappedMSP) Unwrap() MSP {
+	return w.MSP
+}
+
+func parseTestCert(t *testing.T, pemBytes []byte) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode(pemBytes)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/msp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSatisfiesPrincipalFunc(t *testing.T) {
+	t.Parallel()
+	// testdata/nodeous3 enables NodeOUs, and its default signing identity is a peer.
+	thisMSP := getLocalMSPWithVersion(t, "testdata/nodeous3", MSPv1_1)
+	id, err := thisMSP.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	mspID, err := thisMSP.GetIdentifier()
+	require.NoError(t, err)
+
+	rolePrincipal := func(role msp.MSPRole_MSPRoleType, mspID string) *msp.MSPPrincipal {
+		principalBytes, err := proto.Marshal(&msp.MSPRole{Role: role, MspIdentifier: mspID})
+		require.NoError(t, err)
+		return &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_ROLE, Principal: principalBytes}
+	}
+
+	require.NoError(t, SatisfiesPrincipal(thisMSP, id, rolePrincipal(msp.MSPRole_PEER, mspID)))
+	require.NoError(t, SatisfiesPrincipal(thisMSP, id, rolePrincipal(msp.MSPRole_MEMBER, mspID)))
+
+	err = SatisfiesPrincipal(thisMSP, id, rolePrincipal(msp.MSPRole_MEMBER, "OtherMSP"))
+	require.ErrorContains(t, err, "the identity is a member of a different MSP")
+
+	err = SatisfiesPrincipal(thisMSP, id, &msp.MSPPrincipal{PrincipalClassification: msp.MSPPrincipal_ANONYMITY})
+	require.EqualError(t, err, "unsupported principal classification ANONYMITY")
+
+	err = SatisfiesPrincipal(thisMSP, nil, rolePrincipal(msp.MSPRole_PEER, mspID))
+	require.EqualError(t, err, "identity must not be nil")
+	err = SatisfiesPrincipal(thisMSP, id, nil)
+	require.EqualError(t, err, "principal must not be nil")
+}
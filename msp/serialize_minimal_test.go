@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger/fabric-x-common/api/msppb"
+)
+
+func TestSerializeMinimal(t *testing.T) {
+	t.Parallel()
+	signer, err := localMsp.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	serialized, err := signer.Serialize()
+	require.NoError(t, err)
+
+	minimal, err := SerializeMinimal(signer)
+	require.NoError(t, err)
+	require.Less(t, len(minimal), len(serialized))
+
+	id, err := DeserializeMinimal(localMsp, minimal)
+	require.NoError(t, err)
+	require.Equal(t, signer.GetIdentifier(), id.GetIdentifier())
+	roundTrip, err := id.Serialize()
+	require.NoError(t, err)
+	require.Equal(t, serialized, roundTrip)
+
+	// The regular deserialization expects a PEM certificate.
+	_, err = deserializeAndValidate(localMsp, minimal)
+	require.ErrorContains(t, err, "could not decode the PEM structure")
+
+	withIDOfCert, err := NewSerializedIdentityWithIDOfCert(signer.GetMSPIdentifier(), "cert-id")
+	require.NoError(t, err)
+	_, err = DeserializeMinimal(localMsp, withIDOfCert)
+	require.EqualError(t, err, "the identity has no certificate")
+
+	corrupt, err := proto.Marshal(msppb.NewIdentity(signer.GetMSPIdentifier(), []byte("not a certificate")))
+	require.NoError(t, err)
+	_, err = DeserializeMinimal(localMsp, corrupt)
+	require.ErrorContains(t, err, "parseCertificate failed")
+}
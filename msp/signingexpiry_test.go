@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-lib-go/bccsp/sw"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestSigningIdentityExpiry(t *testing.T) {
+	t.Parallel()
+	mspDir := configtest.GetDevMspDir()
+	thisMSP := getLocalMSP(t, mspDir)
+
+	pems, err := getPemMaterialFromDir(filepath.Join(mspDir, signcerts))
+	require.NoError(t, err)
+	require.Len(t, pems, 1)
+	cert := parseTestCert(t, pems[0])
+
+	expiry, err := SigningIdentityExpiry(thisMSP)
+	require.NoError(t, err)
+	require.True(t, expiry.Equal(cert.NotAfter))
+	require.True(t, expiry.After(cert.NotBefore))
+}
+
+func TestSigningIdentityExpiryNoSigner(t *testing.T) {
+	t.Parallel()
+	conf, err := GetVerifyingMspConfig(configtest.GetDevMspDir(), "SampleOrg", ProviderTypeToString(FABRIC))
+	require.NoError(t, err)
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	thisMSP, err := NewBccspMspWithKeyStore(MSPv1_0, sw.NewDummyKeyStore(), cryptoProvider)
+	require.NoError(t, err)
+	require.NoError(t, thisMSP.Setup(conf))
+
+	_, err = SigningIdentityExpiry(thisMSP)
+	require.ErrorContains(t, err, "failed to get the default signing identity")
+}
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateIdentityAtTime(t *testing.T) {
+	t.Parallel()
+	signer, err := localMsp.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	serialized, err := signer.Serialize()
+	require.NoError(t, err)
+	cert := signer.(*signingidentity).cert
+
+	require.NoError(t, ValidateIdentityAtTime(localMsp, serialized, cert.NotBefore.Add(time.Hour)))
+
+	err = ValidateIdentityAtTime(localMsp, serialized, cert.NotAfter.Add(time.Second))
+	require.EqualError(t, err, "identity expired at "+cert.NotAfter.UTC().Format(time.RFC3339))
+
+	err = ValidateIdentityAtTime(localMsp, serialized, cert.NotBefore.Add(-time.Second))
+	require.EqualError(t, err, "identity is not valid before "+cert.NotBefore.UTC().Format(time.RFC3339))
+
+	err = ValidateIdentityAtTime(localMsp, []byte("garbage"), time.Now())
+	require.ErrorContains(t, err, "could not deserialize a SerializedIdentity")
+}
@@ -7,8 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package mspext_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/msp"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger/fabric-x-common/api/msppb"
 	"github.com/hyperledger/fabric-x-common/protolator"
 	"github.com/hyperledger/fabric-x-common/protolator/protoext/mspext"
+	"github.com/hyperledger/fabric-x-common/protoutil"
 )
 
 // ensure structs implement expected interfaces
@@ -19,3 +29,46 @@ var (
 	_ protolator.VariablyOpaqueFieldProto = &mspext.MSPPrincipal{}
 	_ protolator.DecoratedProto           = &mspext.MSPPrincipal{}
 )
+
+func TestMSPConfigNodeOUs(t *testing.T) {
+	t.Parallel()
+	nodeOUs := &msp.FabricNodeOUs{
+		Enable:              true,
+		ClientOuIdentifier:  &msp.FabricOUIdentifier{Certificate: []byte("ca-cert"), OrganizationalUnitIdentifier: "client"},
+		PeerOuIdentifier:    &msp.FabricOUIdentifier{Certificate: []byte("ca-cert"), OrganizationalUnitIdentifier: "peer"},
+		AdminOuIdentifier:   &msp.FabricOUIdentifier{Certificate: []byte("ca-cert"), OrganizationalUnitIdentifier: "admin"},
+		OrdererOuIdentifier: &msp.FabricOUIdentifier{OrganizationalUnitIdentifier: "orderer"},
+	}
+	mspConfig := &msp.MSPConfig{
+		Config: protoutil.MarshalOrPanic(&msppb.FabricMSPConfig{Name: "Org1MSP", FabricNodeOus: nodeOUs}),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, protolator.DeepMarshalJSON(&buf, mspConfig))
+
+	var rendered struct {
+		Config struct {
+			FabricNodeOUs struct {
+				Enable             bool `json:"enable"`
+				ClientOUIdentifier struct {
+					Certificate                  []byte `json:"certificate"`
+					OrganizationalUnitIdentifier string `json:"organizational_unit_identifier"`
+				} `json:"client_ou_identifier"`
+				OrdererOUIdentifier struct {
+					OrganizationalUnitIdentifier string `json:"organizational_unit_identifier"`
+				} `json:"orderer_ou_identifier"`
+			} `json:"fabric_node_ous"`
+		} `json:"config"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rendered))
+	require.True(t, rendered.Config.FabricNodeOUs.Enable)
+	require.Equal(t, []byte("ca-cert"), rendered.Config.FabricNodeOUs.ClientOUIdentifier.Certificate)
+	require.Equal(t, "client", rendered.Config.FabricNodeOUs.ClientOUIdentifier.OrganizationalUnitIdentifier)
+	require.Equal(t, "orderer", rendered.Config.FabricNodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier)
+
+	decoded := &msp.MSPConfig{}
+	require.NoError(t, protolator.DeepUnmarshalJSON(&buf, decoded))
+	fabricConfig := &msppb.FabricMSPConfig{}
+	require.NoError(t, proto.Unmarshal(decoded.Config, fabricConfig))
+	require.True(t, proto.Equal(nodeOUs, fabricConfig.FabricNodeOus))
+}
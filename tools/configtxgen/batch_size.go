@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/hyperledger/fabric-x-common/common/viperutil"
+)
+
+// OverrideBatchSize overrides the fields of the orderer BatchSize of the profile. A zero message count
+// or an empty byte size keeps the profile's value. Byte sizes are either plain numbers, or use the same
+// units as configtx.yaml, e.g., "10 MB".
+func OverrideBatchSize(p *Profile, maxMessageCount uint32, absoluteMaxBytes, preferredMaxBytes string) error {
+	if p.Orderer == nil {
+		return errors.New("the profile has no orderer section")
+	}
+	batchSize := p.Orderer.BatchSize
+	if maxMessageCount > 0 {
+		batchSize.MaxMessageCount = maxMessageCount
+	}
+	if absoluteMaxBytes != "" {
+		size, err := parseByteSize(absoluteMaxBytes)
+		if err != nil {
+			return errors.WithMessage(err, "invalid absolute max bytes")
+		}
+		batchSize.AbsoluteMaxBytes = size
+	}
+	if preferredMaxBytes != "" {
+		size, err := parseByteSize(preferredMaxBytes)
+		if err != nil {
+			return errors.WithMessage(err, "invalid preferred max bytes")
+		}
+		batchSize.PreferredMaxBytes = size
+	}
+	if batchSize.PreferredMaxBytes > batchSize.AbsoluteMaxBytes {
+		return errors.Newf("preferred max bytes (%d) must not exceed absolute max bytes (%d)",
+			batchSize.PreferredMaxBytes, batchSize.AbsoluteMaxBytes)
+	}
+	p.Orderer.BatchSize = batchSize
+	return nil
+}
+
+// parseByteSize parses a byte size, as configtx.yaml does.
+func parseByteSize(raw string) (uint32, error) {
+	value, err := viperutil.ByteSizeDecodeHook(reflect.TypeOf(raw), reflect.TypeOf(uint32(0)), raw)
+	if err != nil {
+		return 0, err
+	}
+	if size, ok := value.(uint32); ok {
+		return size, nil
+	}
+	size, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return 0, errors.Newf("'%s' is not a byte size", raw)
+	}
+	return uint32(size), nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestOverrideBatchSize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("overrides the given fields", func(t *testing.T) {
+		t.Parallel()
+		p := Load(SampleAppChannelSmartBftProfile, configtest.GetDevConfigDir())
+		original := p.Orderer.BatchSize
+
+		require.NoError(t, OverrideBatchSize(p, 0, "", "512 KB"))
+		require.Equal(t, original.MaxMessageCount, p.Orderer.BatchSize.MaxMessageCount)
+		require.Equal(t, original.AbsoluteMaxBytes, p.Orderer.BatchSize.AbsoluteMaxBytes)
+		require.Equal(t, uint32(512*1024), p.Orderer.BatchSize.PreferredMaxBytes)
+	})
+
+	t.Run("rejects preferred larger than absolute", func(t *testing.T) {
+		t.Parallel()
+		p := Load(SampleAppChannelSmartBftProfile, configtest.GetDevConfigDir())
+		original := p.Orderer.BatchSize
+
+		err := OverrideBatchSize(p, 10, "1 MB", "2 MB")
+		require.EqualError(t, err, "preferred max bytes (2097152) must not exceed absolute max bytes (1048576)")
+		require.Equal(t, original, p.Orderer.BatchSize)
+	})
+
+	t.Run("rejects malformed byte sizes", func(t *testing.T) {
+		t.Parallel()
+		p := Load(SampleAppChannelSmartBftProfile, configtest.GetDevConfigDir())
+
+		err := OverrideBatchSize(p, 0, "lots", "")
+		require.EqualError(t, err, "invalid absolute max bytes: 'lots' is not a byte size")
+	})
+
+	t.Run("requires an orderer section", func(t *testing.T) {
+		t.Parallel()
+		err := OverrideBatchSize(&Profile{}, 10, "", "")
+		require.EqualError(t, err, "the profile has no orderer section")
+	})
+}
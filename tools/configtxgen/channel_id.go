@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"github.com/cockroachdb/errors"
+
+	"github.com/hyperledger/fabric-x-common/common/configtx"
+)
+
+// ValidateChannelID checks that a channel ID follows the Fabric channel naming rules:
+// it must start with a lowercase letter, contain only lowercase letters, digits, dots
+// and dashes, and be at most configtx.MaxLength characters long.
+// Unlike configtx.ValidateChannelID, the error points at the offending character.
+func ValidateChannelID(id string) error {
+	if id == "" {
+		return errors.New("channel ID must not be empty")
+	}
+	if len(id) > configtx.MaxLength {
+		return errors.Newf("channel ID is %d characters long, but at most %d are allowed", len(id), configtx.MaxLength)
+	}
+	if !isLowerLetter(id[0]) {
+		return errors.Newf("channel ID '%s' must start with a lowercase letter, but starts with '%c'", id, id[0])
+	}
+	for i := range len(id) {
+		c := id[i]
+		if !isLowerLetter(c) && !('0' <= c && c <= '9') && c != '.' && c != '-' {
+			return errors.Newf("channel ID '%s' contains the illegal character '%c' at position %d; "+
+				"only lowercase letters, digits, '.' and '-' are allowed", id, c, i)
+		}
+	}
+	return nil
+}
+
+func isLowerLetter(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/common/configtx"
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestValidateChannelID(t *testing.T) {
+	t.Parallel()
+	for _, id := range []string{"a", "mychannel", "channel-0", "org1.channel-2", strings.Repeat("a", configtx.MaxLength)} {
+		require.NoError(t, ValidateChannelID(id), id)
+	}
+
+	for _, tc := range []struct {
+		id  string
+		err string
+	}{
+		{id: "", err: "channel ID must not be empty"},
+		{
+			id:  strings.Repeat("a", configtx.MaxLength+1),
+			err: "channel ID is 250 characters long, but at most 249 are allowed",
+		},
+		{id: "1channel", err: "channel ID '1channel' must start with a lowercase letter, but starts with '1'"},
+		{id: ".channel", err: "channel ID '.channel' must start with a lowercase letter, but starts with '.'"},
+		{
+			id: "myChannel",
+			err: "channel ID 'myChannel' contains the illegal character 'C' at position 2; " +
+				"only lowercase letters, digits, '.' and '-' are allowed",
+		},
+		{
+			id: "my_channel",
+			err: "channel ID 'my_channel' contains the illegal character '_' at position 2; " +
+				"only lowercase letters, digits, '.' and '-' are allowed",
+		},
+	} {
+		require.EqualError(t, ValidateChannelID(tc.id), tc.err, tc.id)
+	}
+}
+
+func TestDoOutputInvalidChannelID(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+
+	err := DoOutputBlock(config, "my_channel", filepath.Join(dir, "block"))
+	require.ErrorContains(t, err, "illegal character '_'")
+	require.NoFileExists(t, filepath.Join(dir, "block"))
+
+	err = DoOutputChannelCreateTx(config, nil, "my_channel", filepath.Join(dir, "tx"))
+	require.ErrorContains(t, err, "illegal character '_'")
+	require.NoFileExists(t, filepath.Join(dir, "tx"))
+}
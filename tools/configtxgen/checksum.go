@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ChecksumFileExt is the extension of the checksum sidecar of an artifact.
+const ChecksumFileExt = ".sha256"
+
+// WriteChecksum writes the SHA-256 checksum of the block file to a sidecar file next to it,
+// named after the block file with the ChecksumFileExt extension.
+// The sidecar has the format of sha256sum, so it may also be checked with "sha256sum -c".
+func WriteChecksum(blockPath string) error {
+	digest, err := fileDigest(blockPath)
+	if err != nil {
+		return err
+	}
+	content := fmt.Sprintf("%s  %s\n", digest, filepath.Base(blockPath))
+	err = writeFile(blockPath+ChecksumFileExt, []byte(content), 0o640)
+	if err != nil {
+		return errors.Wrap(err, "error writing checksum")
+	}
+	return nil
+}
+
+// VerifyChecksum checks the block file against the checksum written by WriteChecksum.
+func VerifyChecksum(blockPath string) error {
+	sidecar, err := os.ReadFile(blockPath + ChecksumFileExt)
+	if err != nil {
+		return errors.Wrap(err, "could not read checksum")
+	}
+	expected, name, ok := strings.Cut(strings.TrimSpace(string(sidecar)), "  ")
+	if !ok || name != filepath.Base(blockPath) {
+		return errors.Newf("malformed checksum of %s", blockPath)
+	}
+	actual, err := fileDigest(blockPath)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return errors.Newf("checksum mismatch for %s: expected %s, got %s", blockPath, expected, actual)
+	}
+	return nil
+}
+
+func fileDigest(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read %s", path)
+	}
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:]), nil
+}
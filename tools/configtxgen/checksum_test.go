@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestChecksum(t *testing.T) {
+	t.Parallel()
+	blockDest := filepath.Join(t.TempDir(), "block")
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	require.NoError(t, DoOutputBlock(config, "foo", blockDest))
+
+	err := VerifyChecksum(blockDest)
+	require.ErrorContains(t, err, "could not read checksum")
+
+	require.NoError(t, WriteChecksum(blockDest))
+	block, err := os.ReadFile(blockDest)
+	require.NoError(t, err)
+	digest := sha256.Sum256(block)
+	sidecar, err := os.ReadFile(blockDest + ChecksumFileExt)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(digest[:])+"  block\n", string(sidecar))
+	require.NoError(t, VerifyChecksum(blockDest))
+
+	// Tampering with the block is detected.
+	block[len(block)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(blockDest, block, 0o600))
+	err = VerifyChecksum(blockDest)
+	require.ErrorContains(t, err, "checksum mismatch for "+blockDest)
+
+	require.NoError(t, os.WriteFile(blockDest+ChecksumFileExt, []byte("garbage"), 0o600))
+	err = VerifyChecksum(blockDest)
+	require.EqualError(t, err, "malformed checksum of "+blockDest)
+
+	err = WriteChecksum(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorContains(t, err, "could not read")
+}
@@ -101,6 +101,13 @@ type Profile struct {
 	Consortiums  map[string]*Consortium `yaml:"Consortiums"`
 	Capabilities map[string]bool        `yaml:"Capabilities"`
 	Policies     map[string]*Policy     `yaml:"Policies"`
+	// ModPolicies overrides the mod_policy of specific config elements. It maps an element path,
+	// relative to the channel group (e.g., "Application" or "Orderer/BatchSize"), to a policy
+	// reference, either relative to the element's group or absolute (e.g., "/Channel/Orderer/Admins").
+	ModPolicies map[string]string `yaml:"ModPolicies"`
+	// OrdererOnly marks a profile of a channel used by the ordering service only,
+	// which does not require an application section.
+	OrdererOnly bool `yaml:"OrdererOnly"`
 }
 
 // Policy encodes a channel config policy.
@@ -187,6 +194,8 @@ type Consenter struct {
 
 type ConsensusMetadata struct {
 	Path string `yaml:"Path"`
+	// Bytes holds the consensus metadata in memory, for programmatic callers. It takes precedence over Path.
+	Bytes []byte `yaml:"-"`
 }
 
 func genesisOrdererDefaults() Orderer {
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"github.com/cockroachdb/errors"
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/common/configtx"
+	"github.com/hyperledger/fabric-x-common/protoutil"
+)
+
+// NextConfigBlockNumber returns the number of the next config block of a channel with the given height.
+// Blocks are numbered from the genesis block (0), so the next block is numbered by the current height.
+func NextConfigBlockNumber(currentHeight uint64) uint64 {
+	return currentHeight
+}
+
+// NextConfigSequence returns the config sequence expected in the next config block of the channel
+// described by the given bundle. A nil bundle stands for a channel with no config yet, i.e., the genesis block.
+func NextConfigSequence(bundle *channelconfig.Bundle) uint64 {
+	if bundle == nil {
+		return 0
+	}
+	return bundle.ConfigtxValidator().Sequence() + 1
+}
+
+// ValidateNextConfigBlock checks that the given block may follow a channel with the given height and
+// config bundle: its number must match NextConfigBlockNumber and its config sequence NextConfigSequence.
+func ValidateNextConfigBlock(bundle *channelconfig.Bundle, block *cb.Block, currentHeight uint64) error {
+	if block == nil || block.Header == nil {
+		return errors.New("block must not be nil")
+	}
+	if bundle == nil && currentHeight != 0 {
+		return errors.Newf("a config bundle is required at height %d", currentHeight)
+	}
+	if expected := NextConfigBlockNumber(currentHeight); block.Header.Number != expected {
+		return errors.Newf("config block number %d does not match the expected number %d",
+			block.Header.Number, expected)
+	}
+
+	sequence, err := configBlockSequence(block)
+	if err != nil {
+		return err
+	}
+	if expected := NextConfigSequence(bundle); sequence != expected {
+		return errors.Newf("config sequence %d does not match the expected sequence %d", sequence, expected)
+	}
+	return nil
+}
+
+// configBlockSequence returns the config sequence of a config block.
+func configBlockSequence(block *cb.Block) (uint64, error) {
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to extract envelope from block")
+	}
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to unmarshal payload")
+	}
+	if payload.Header == nil {
+		return 0, errors.New("payload header is missing")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to unmarshal channel header")
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return 0, errors.Newf("block is not a config block, its header type is %s", cb.HeaderType(chdr.Type))
+	}
+	configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return 0, err
+	}
+	if configEnvelope.Config == nil {
+		return 0, errors.New("config envelope has no config")
+	}
+	return configEnvelope.Config.Sequence, nil
+}
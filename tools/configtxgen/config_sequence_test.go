@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-lib-go/bccsp/factory"
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+	"github.com/hyperledger/fabric-x-common/protoutil"
+)
+
+func TestNextConfigBlockNumber(t *testing.T) {
+	t.Parallel()
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	genesisBlock, err := GetOutputBlock(config, "foo")
+	require.NoError(t, err)
+
+	require.Zero(t, NextConfigBlockNumber(0))
+	require.Zero(t, NextConfigSequence(nil))
+	require.NoError(t, ValidateNextConfigBlock(nil, genesisBlock, 0))
+
+	envelope, err := protoutil.ExtractEnvelope(genesisBlock, 0)
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromEnvelope(envelope, factory.GetDefault())
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(1), NextConfigBlockNumber(1))
+	require.Equal(t, uint64(5), NextConfigBlockNumber(5))
+	require.Equal(t, uint64(1), NextConfigSequence(bundle))
+
+	channelGroup := bundle.ConfigtxValidator().ConfigProto().ChannelGroup
+	require.NoError(t, ValidateNextConfigBlock(bundle, newConfigBlock(t, 5, 1, channelGroup), 5))
+
+	err = ValidateNextConfigBlock(bundle, newConfigBlock(t, 4, 1, channelGroup), 5)
+	require.EqualError(t, err, "config block number 4 does not match the expected number 5")
+	err = ValidateNextConfigBlock(bundle, newConfigBlock(t, 5, 2, channelGroup), 5)
+	require.EqualError(t, err, "config sequence 2 does not match the expected sequence 1")
+	err = ValidateNextConfigBlock(nil, newConfigBlock(t, 5, 1, channelGroup), 5)
+	require.EqualError(t, err, "a config bundle is required at height 5")
+	err = ValidateNextConfigBlock(bundle, nil, 5)
+	require.EqualError(t, err, "block must not be nil")
+
+	txBlock := protoutil.NewBlock(5, nil)
+	txEnvelope, err := protoutil.CreateSignedEnvelope(cb.HeaderType_MESSAGE, "foo", nil, &cb.Envelope{}, 0, 0)
+	require.NoError(t, err)
+	txBlock.Data.Data = [][]byte{protoutil.MarshalOrPanic(txEnvelope)}
+	err = ValidateNextConfigBlock(bundle, txBlock, 5)
+	require.EqualError(t, err, "block is not a config block, its header type is MESSAGE")
+}
+
+func newConfigBlock(t *testing.T, number, sequence uint64, channelGroup *cb.ConfigGroup) *cb.Block {
+	t.Helper()
+	configEnvelope := &cb.ConfigEnvelope{Config: &cb.Config{Sequence: sequence, ChannelGroup: channelGroup}}
+	envelope, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG, "foo", nil, configEnvelope, 0, 0)
+	require.NoError(t, err)
+	block := protoutil.NewBlock(number, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(envelope)}
+	return block
+}
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"path"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+)
+
+// DescribePolicies returns the policies defined by a profile, mapping each policy path to its
+// human-readable rule, e.g., "Application/Admins" to "ImplicitMeta MAJORITY Admins".
+// Paths are relative to the channel group, as in Profile.ModPolicies.
+// Organizations skipped as foreign are not described, as their policies are not encoded.
+func DescribePolicies(p *Profile) map[string]string {
+	policies := make(map[string]string)
+	if p == nil {
+		return policies
+	}
+	describePolicies(policies, "", p.Policies)
+	if p.Orderer != nil {
+		describePolicies(policies, channelconfig.OrdererGroupKey, p.Orderer.Policies)
+		describeOrgPolicies(policies, channelconfig.OrdererGroupKey, p.Orderer.Organizations)
+	}
+	if p.Application != nil {
+		describePolicies(policies, channelconfig.ApplicationGroupKey, p.Application.Policies)
+		describeOrgPolicies(policies, channelconfig.ApplicationGroupKey, p.Application.Organizations)
+	}
+	for name, consortium := range p.Consortiums {
+		describeOrgPolicies(policies, path.Join(channelconfig.ConsortiumsGroupKey, name), consortium.Organizations)
+	}
+	return policies
+}
+
+func describeOrgPolicies(policies map[string]string, groupPath string, orgs []*Organization) {
+	for _, org := range orgs {
+		if org.SkipAsForeign {
+			continue
+		}
+		describePolicies(policies, path.Join(groupPath, org.Name), org.Policies)
+	}
+}
+
+func describePolicies(policies map[string]string, groupPath string, groupPolicies map[string]*Policy) {
+	for name, policy := range groupPolicies {
+		policies[path.Join(groupPath, name)] = policy.Type + " " + policy.Rule
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestDescribePolicies(t *testing.T) {
+	t.Parallel()
+	policies := DescribePolicies(Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir()))
+
+	for policyPath, rule := range map[string]string{
+		"Admins":                        "ImplicitMeta MAJORITY Admins",
+		"Readers":                       "ImplicitMeta ANY Readers",
+		"Orderer/BlockValidation":       "ImplicitMeta ANY Writers",
+		"Application/Admins":            "ImplicitMeta MAJORITY Admins",
+		"Orderer/SampleOrg/Admins":      "Signature OR('SampleOrg.member')",
+		"Application/SampleOrg/Readers": "Signature OR('SampleOrg.member')",
+		"Consortiums/SampleConsortium/SampleOrg/Admins": "Signature OR('SampleOrg.member')",
+	} {
+		require.Equal(t, rule, policies[policyPath], policyPath)
+	}
+	require.Empty(t, DescribePolicies(nil))
+}
@@ -9,6 +9,7 @@ package configtxgen
 import (
 	"fmt"
 	"os"
+	"time"
 
 	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
 	"github.com/hyperledger/fabric-protos-go-apiv2/orderer/smartbft"
@@ -16,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/hyperledger/fabric-x-common/api/types"
 	"github.com/hyperledger/fabric-x-common/common/channelconfig"
 	"github.com/hyperledger/fabric-x-common/common/genesis"
 	"github.com/hyperledger/fabric-x-common/common/policies"
@@ -96,38 +98,51 @@ func addPolicies(cg *cb.ConfigGroup, policyMap map[string]*Policy, modPolicy str
 	}
 
 	for policyName, policy := range policyMap {
-		switch policy.Type {
-		case ImplicitMetaPolicyType:
-			imp, err := policies.ImplicitMetaFromString(policy.Rule)
-			if err != nil {
-				return errors.Wrapf(err, "invalid implicit meta policy rule '%s'", policy.Rule)
-			}
-			cg.Policies[policyName] = &cb.ConfigPolicy{
-				ModPolicy: modPolicy,
-				Policy: &cb.Policy{
-					Type:  int32(cb.Policy_IMPLICIT_META),
-					Value: protoutil.MarshalOrPanic(imp),
-				},
-			}
-		case SignaturePolicyType:
-			sp, err := policydsl.FromString(policy.Rule)
-			if err != nil {
-				return errors.Wrapf(err, "invalid signature policy rule '%s'", policy.Rule)
-			}
-			cg.Policies[policyName] = &cb.ConfigPolicy{
-				ModPolicy: modPolicy,
-				Policy: &cb.Policy{
-					Type:  int32(cb.Policy_SIGNATURE),
-					Value: protoutil.MarshalOrPanic(sp),
-				},
-			}
-		default:
-			return errors.Errorf("unknown policy type: %s", policy.Type)
+		p, err := policyFromRule(policy.Type, policy.Rule)
+		if err != nil {
+			return err
+		}
+		cg.Policies[policyName] = &cb.ConfigPolicy{
+			ModPolicy: modPolicy,
+			Policy:    p,
 		}
 	}
 	return nil
 }
 
+// ValidatePolicyRule checks that the rule is valid for the policy type, as when encoding a profile's policy.
+// The type is either SignaturePolicyType, with a rule in the signature policy DSL,
+// e.g., "OutOf(2, 'Org1.member', 'Org2.member')", or ImplicitMetaPolicyType, e.g., "MAJORITY Admins".
+func ValidatePolicyRule(typ, rule string) error {
+	_, err := policyFromRule(typ, rule)
+	return err
+}
+
+func policyFromRule(typ, rule string) (*cb.Policy, error) {
+	switch typ {
+	case ImplicitMetaPolicyType:
+		imp, err := policies.ImplicitMetaFromString(rule)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid implicit meta policy rule '%s'", rule)
+		}
+		return &cb.Policy{
+			Type:  int32(cb.Policy_IMPLICIT_META),
+			Value: protoutil.MarshalOrPanic(imp),
+		}, nil
+	case SignaturePolicyType:
+		sp, err := policydsl.FromString(rule)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid signature policy rule '%s'", rule)
+		}
+		return &cb.Policy{
+			Type:  int32(cb.Policy_SIGNATURE),
+			Value: protoutil.MarshalOrPanic(sp),
+		}, nil
+	default:
+		return nil, errors.Errorf("unknown policy type: %s", typ)
+	}
+}
+
 // NewChannelGroup defines the root of the channel configuration.  It defines basic operating principles like the hashing
 // algorithm used for the blocks, as well as the location of the ordering service.  It will recursively call into the
 // NewOrdererGroup, NewConsortiumsGroup, and NewApplicationGroup depending on whether these sub-elements are set in the
@@ -176,6 +191,12 @@ func NewChannelGroup(conf *Profile) (*cb.ConfigGroup, error) {
 	}
 
 	channelGroup.ModPolicy = channelconfig.AdminsPolicyKey
+	if err = applyModPolicies(channelGroup, conf.ModPolicies); err != nil {
+		return nil, err
+	}
+	for _, warning := range unsatisfiedImplicitMetaPolicies("/"+channelconfig.RootGroupKey, channelGroup) {
+		logger.Warn(warning)
+	}
 	return channelGroup, nil
 }
 
@@ -210,6 +231,9 @@ func NewOrdererGroup(conf *Orderer, channelCapabilities map[string]bool) (*cb.Co
 
 	switch conf.OrdererType {
 	case ConsensusTypeSolo:
+		if err = validateSoloOrderer(conf); err != nil {
+			return nil, err
+		}
 	case ConsensusTypeEtcdRaft:
 		if consensusMetadata, err = channelconfig.MarshalEtcdRaftMetadata(conf.EtcdRaft); err != nil {
 			return nil, errors.Errorf("cannot marshal metadata for orderer type %s: %s", ConsensusTypeEtcdRaft, err)
@@ -233,7 +257,14 @@ func NewOrdererGroup(conf *Orderer, channelCapabilities map[string]bool) (*cb.Co
 			return nil, errors.Errorf("cannot load consenter config for orderer type %s: %s", ConsensusTypeArma, err)
 		}
 		addValue(ordererGroup, channelconfig.OrderersValue(consenterProtos), channelconfig.AdminsPolicyKey)
-		if conf.Arma.Path != "" {
+		switch {
+		case len(conf.Arma.Bytes) > 0:
+			if conf.Arma.Path != "" {
+				logger.Warningf("Both the path and the bytes of the %s metadata are set, ignoring the path %s",
+					conf.OrdererType, conf.Arma.Path)
+			}
+			consensusMetadata = conf.Arma.Bytes
+		case conf.Arma.Path != "":
 			if consensusMetadata, err = os.ReadFile(conf.Arma.Path); err != nil {
 				return nil, errors.Errorf("cannot load metadata for orderer type %s: %s", conf.OrdererType, err)
 			}
@@ -258,6 +289,25 @@ func NewOrdererGroup(conf *Orderer, channelCapabilities map[string]bool) (*cb.Co
 	return ordererGroup, nil
 }
 
+// validateSoloOrderer rejects solo configurations with more than a single consenter or orderer endpoint,
+// as a solo orderer is a single node. Profiles that inherit a consenter mapping from the orderer defaults
+// must override it for solo.
+func validateSoloOrderer(conf *Orderer) error {
+	if len(conf.ConsenterMapping) > 1 {
+		return errors.Errorf("orderer type %s supports a single consenter, but %d are configured",
+			ConsensusTypeSolo, len(conf.ConsenterMapping))
+	}
+	endpoints := 0
+	for _, org := range conf.Organizations {
+		endpoints += len(org.OrdererEndpoints)
+	}
+	if endpoints > 1 {
+		return errors.Errorf("orderer type %s supports a single orderer endpoint, but %d are configured",
+			ConsensusTypeSolo, endpoints)
+	}
+	return nil
+}
+
 func consenterProtosFromConfig(consenterMapping []*Consenter) ([]*cb.Consenter, error) {
 	var consenterProtos []*cb.Consenter
 	for _, consenter := range consenterMapping {
@@ -350,6 +400,9 @@ func NewOrdererOrgGroup(conf *Organization, channelCapabilities map[string]bool)
 	addValue(ordererOrgGroup, channelconfig.MSPValue(mspConfig), channelconfig.AdminsPolicyKey)
 
 	if len(conf.OrdererEndpoints) > 0 {
+		if err := validateOrdererEndpointsAPI(conf); err != nil {
+			return nil, err
+		}
 		endpoints := make([]string, len(conf.OrdererEndpoints))
 		for i, e := range conf.OrdererEndpoints {
 			endpoints[i] = e.String()
@@ -362,6 +415,29 @@ func NewOrdererOrgGroup(conf *Organization, channelCapabilities map[string]bool)
 	return ordererOrgGroup, nil
 }
 
+// validateOrdererEndpointsAPI rejects endpoints with unknown API tokens, and warns
+// if none of the organization's endpoints exposes the broadcast or the deliver API.
+func validateOrdererEndpointsAPI(conf *Organization) error {
+	supported := map[string]bool{}
+	for _, e := range conf.OrdererEndpoints {
+		for _, api := range e.API {
+			if api != types.Broadcast && api != types.Deliver {
+				return errors.Errorf("orderer endpoint [%s] of organization %s has an unknown API '%s', expected '%s' or '%s'",
+					e.String(), conf.Name, api, types.Broadcast, types.Deliver)
+			}
+		}
+		for _, api := range []string{types.Broadcast, types.Deliver} {
+			supported[api] = supported[api] || e.SupportsAPI(api)
+		}
+	}
+	for _, api := range []string{types.Broadcast, types.Deliver} {
+		if !supported[api] {
+			logger.Warnf("None of the orderer endpoints of organization %s exposes the %s API", conf.Name, api)
+		}
+	}
+	return nil
+}
+
 // NewApplicationGroup returns the application component of the channel configuration.  It defines the organizations which are involved
 // in application logic like chaincodes, and how these members may interact with the orderer.  It sets the mod_policy of all elements to "Admins".
 func NewApplicationGroup(conf *Application) (*cb.ConfigGroup, error) {
@@ -512,7 +588,7 @@ func NewChannelCreateConfigUpdate(
 
 // DefaultConfigTemplate generates a config template based on the assumption that
 // the input profile is a channel creation template and no system channel context
-// is available.
+// is available. The template of an orderer-only profile has no application section.
 func DefaultConfigTemplate(conf *Profile) (*cb.ConfigGroup, error) {
 	channelGroup, err := NewChannelGroup(conf)
 	if err != nil {
@@ -520,6 +596,12 @@ func DefaultConfigTemplate(conf *Profile) (*cb.ConfigGroup, error) {
 	}
 
 	if _, ok := channelGroup.Groups[channelconfig.ApplicationGroupKey]; !ok {
+		if conf.OrdererOnly {
+			if _, ok := channelGroup.Groups[channelconfig.OrdererGroupKey]; !ok {
+				return nil, errors.New("orderer-only channel template configs must contain an orderer section")
+			}
+			return channelGroup, nil
+		}
 		return nil, errors.New("channel template configs must contain an application section")
 	}
 
@@ -631,26 +713,34 @@ func MakeChannelCreationTransactionFromTemplate(
 	}
 
 	if signer != nil {
-		sigHeader, err := protoutil.NewSignatureHeader(signer)
+		configSig, err := newConfigSignature(signer, newConfigUpdateEnv.ConfigUpdate)
 		if err != nil {
-			return nil, errors.Wrap(err, "creating signature header failed")
+			return nil, err
 		}
-
-		newConfigUpdateEnv.Signatures = []*cb.ConfigSignature{{
-			SignatureHeader: protoutil.MarshalOrPanic(sigHeader),
-		}}
-
-		newConfigUpdateEnv.Signatures[0].Signature, err = signer.Sign(util.ConcatenateBytes(newConfigUpdateEnv.Signatures[0].SignatureHeader, newConfigUpdateEnv.ConfigUpdate))
-		if err != nil {
-			return nil, errors.Wrap(err, "signature failure over config update")
-		}
-
+		newConfigUpdateEnv.Signatures = []*cb.ConfigSignature{configSig}
 	}
 
 	return protoutil.CreateSignedEnvelope(
 		cb.HeaderType_CONFIG_UPDATE, channelID, signer, newConfigUpdateEnv, msgVersion, epoch)
 }
 
+// newConfigSignature signs a marshaled config update with the given signer.
+func newConfigSignature(signer identity.SignerSerializer, configUpdate []byte) (*cb.ConfigSignature, error) {
+	sigHeader, err := protoutil.NewSignatureHeader(signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating signature header failed")
+	}
+
+	configSig := &cb.ConfigSignature{
+		SignatureHeader: protoutil.MarshalOrPanic(sigHeader),
+	}
+	configSig.Signature, err = signer.Sign(util.ConcatenateBytes(configSig.SignatureHeader, configUpdate))
+	if err != nil {
+		return nil, errors.Wrap(err, "signature failure over config update")
+	}
+	return configSig, nil
+}
+
 // HasSkippedForeignOrgs is used to detect whether a configuration includes
 // org definitions which should not be parsed because this tool is being
 // run in a context where the user does not have access to that org's info
@@ -699,7 +789,8 @@ func NewBootstrapper(config *Profile) (*Bootstrapper, error) {
 	}, nil
 }
 
-// New creates a new Bootstrapper for generating genesis blocks
+// New creates a new Bootstrapper for generating genesis blocks.
+// It panics if the config is invalid; use NewBootstrapper to get the error instead.
 func New(config *Profile) *Bootstrapper {
 	bs, err := NewBootstrapper(config)
 	if err != nil {
@@ -719,6 +810,11 @@ func (bs *Bootstrapper) GenesisBlockForChannel(channelID string) *cb.Block {
 	return genesis.NewFactoryImpl(bs.channelGroup).Block(channelID)
 }
 
+// GenesisBlockForChannelAt produces a reproducible genesis block for a given channel ID, with the given timestamp
+func (bs *Bootstrapper) GenesisBlockForChannelAt(channelID string, timestamp time.Time) *cb.Block {
+	return genesis.NewDeterministicFactory(bs.channelGroup, timestamp).Block(channelID)
+}
+
 func (bs *Bootstrapper) GenesisChannelGroup() *cb.ConfigGroup {
 	return bs.channelGroup
 }
@@ -279,6 +279,46 @@ var _ = ginkgo.Describe("Encoder", func() {
 			})
 		})
 
+		ginkgo.Context("when mod policies are overridden", func() {
+			ginkgo.BeforeEach(func() {
+				conf.ModPolicies = map[string]string{
+					"Application":       "Writers",
+					"Orderer/BatchSize": "/Channel/Application/Readers",
+				}
+			})
+
+			ginkgo.It("encodes the custom mod policies", func() {
+				cg, err := NewChannelGroup(conf)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(cg.Groups["Application"].ModPolicy).To(gomega.Equal("Writers"))
+				gomega.Expect(cg.Groups["Orderer"].Values["BatchSize"].ModPolicy).To(
+					gomega.Equal("/Channel/Application/Readers"))
+				gomega.Expect(cg.Groups["Orderer"].ModPolicy).To(gomega.Equal("Admins"))
+				gomega.Expect(cg.ModPolicy).To(gomega.Equal("Admins"))
+			})
+
+			ginkgo.It("rejects a policy that does not exist", func() {
+				conf.ModPolicies = map[string]string{"Application": "Missing"}
+				_, err := NewChannelGroup(conf)
+				gomega.Expect(err).To(gomega.MatchError("invalid mod_policy for 'Application': " +
+					"policy 'Missing' does not exist"))
+			})
+
+			ginkgo.It("rejects an absolute policy path that does not exist", func() {
+				conf.ModPolicies = map[string]string{"Application": "/Channel/Missing/Admins"}
+				_, err := NewChannelGroup(conf)
+				gomega.Expect(err).To(gomega.MatchError("invalid mod_policy for 'Application': " +
+					"policy '/Channel/Missing/Admins': config group 'Missing' not found"))
+			})
+
+			ginkgo.It("rejects an element that does not exist", func() {
+				conf.ModPolicies = map[string]string{"Orderer/Missing": "Admins"}
+				_, err := NewChannelGroup(conf)
+				gomega.Expect(err).To(gomega.MatchError("invalid mod_policy for 'Orderer/Missing': " +
+					"config group or value 'Missing' not found"))
+			})
+		})
+
 		ginkgo.Context("when the orderer addresses are omitted", func() {
 			ginkgo.BeforeEach(func() {
 				conf.Orderer.Addresses = []string{}
@@ -366,7 +406,6 @@ var _ = ginkgo.Describe("Encoder", func() {
 						Policies: CreateStandardPolicies(),
 						OrdererEndpoints: []*types.OrdererEndpoint{
 							{Host: "foo", Port: 7050},
-							{Host: "bar", Port: 8050},
 						},
 					},
 				},
@@ -409,6 +448,34 @@ var _ = ginkgo.Describe("Encoder", func() {
 			})
 		})
 
+		ginkgo.Context("when the consensus type is solo with two endpoints", func() {
+			ginkgo.BeforeEach(func() {
+				conf.Organizations[0].OrdererEndpoints = append(conf.Organizations[0].OrdererEndpoints,
+					&types.OrdererEndpoint{Host: "bar", Port: 8050})
+			})
+
+			ginkgo.It("returns an error", func() {
+				_, err := NewOrdererGroup(conf, channelCapabilities)
+				gomega.Expect(err).To(gomega.MatchError("orderer type solo supports a single orderer endpoint, " +
+					"but 2 are configured"))
+			})
+		})
+
+		ginkgo.Context("when the consensus type is solo with two consenters", func() {
+			ginkgo.BeforeEach(func() {
+				conf.ConsenterMapping = []*Consenter{
+					{ID: 1, Host: "foo", Port: 7050, MSPID: "SampleOrg"},
+					{ID: 2, Host: "bar", Port: 7050, MSPID: "SampleOrg"},
+				}
+			})
+
+			ginkgo.It("returns an error", func() {
+				_, err := NewOrdererGroup(conf, channelCapabilities)
+				gomega.Expect(err).To(gomega.MatchError("orderer type solo supports a single consenter, " +
+					"but 2 are configured"))
+			})
+		})
+
 		ginkgo.Context("when the consensus type is etcd/raft", func() {
 			ginkgo.BeforeEach(func() {
 				conf.OrdererType = "etcdraft"
@@ -730,6 +797,31 @@ var _ = ginkgo.Describe("Encoder", func() {
 			})
 		})
 
+		ginkgo.Context("when an endpoint has an unknown API", func() {
+			ginkgo.BeforeEach(func() {
+				conf.OrdererEndpoints[1].API = []string{types.Deliver, "publish"}
+			})
+
+			ginkgo.It("returns an error", func() {
+				cg, err := NewOrdererOrgGroup(conf, nil)
+				gomega.Expect(err).To(gomega.MatchError("orderer endpoint [id=0,deliver,publish,bar:8050] of " +
+					"organization SampleOrg has an unknown API 'publish', expected 'broadcast' or 'deliver'"))
+				gomega.Expect(cg).To(gomega.BeNil())
+			})
+		})
+
+		ginkgo.Context("when the endpoints have known APIs", func() {
+			ginkgo.BeforeEach(func() {
+				conf.OrdererEndpoints[0].API = []string{types.Broadcast}
+				conf.OrdererEndpoints[1].API = []string{types.Deliver}
+			})
+
+			ginkgo.It("does not produce an error", func() {
+				_, err := NewOrdererOrgGroup(conf, nil)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+		})
+
 		ginkgo.Context("when dev mode is enabled", func() {
 			ginkgo.BeforeEach(func() {
 				conf.AdminPrincipal = "Member"
@@ -1310,6 +1402,19 @@ var _ = ginkgo.Describe("Encoder", func() {
 					gomega.Expect(err).To(gomega.MatchError("channel template configs must contain " +
 						"an application section"))
 				})
+
+				ginkgo.Context("and the profile is orderer-only", func() {
+					ginkgo.BeforeEach(func() {
+						conf.OrdererOnly = true
+					})
+
+					ginkgo.It("returns the template without an application section", func() {
+						cg, err := DefaultConfigTemplate(conf)
+						gomega.Expect(err).NotTo(gomega.HaveOccurred())
+						gomega.Expect(cg.Groups).To(gomega.HaveLen(1))
+						gomega.Expect(cg.Groups["Orderer"]).NotTo(gomega.BeNil())
+					})
+				})
 			})
 		})
 
@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"google.golang.org/protobuf/proto"
+)
+
+// unsatisfiedImplicitMetaPolicies returns a warning for each ImplicitMeta policy of the group, and of its
+// sub-groups, that references a sub-policy which none of the sub-groups of its group defines.
+// Such a policy is meaningless, as it has no sub-policies to evaluate.
+func unsatisfiedImplicitMetaPolicies(groupPath string, group *cb.ConfigGroup) []string {
+	var warnings []string
+	for _, name := range slices.Sorted(maps.Keys(group.Policies)) {
+		policy := group.Policies[name].GetPolicy()
+		if policy.GetType() != int32(cb.Policy_IMPLICIT_META) {
+			continue
+		}
+		implicitMeta := &cb.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Value, implicitMeta); err != nil {
+			// Policies are built by the encoder, so this cannot happen.
+			continue
+		}
+		defined := false
+		for _, subGroup := range group.Groups {
+			if _, ok := subGroup.Policies[implicitMeta.SubPolicy]; ok {
+				defined = true
+				break
+			}
+		}
+		if !defined {
+			warnings = append(warnings, fmt.Sprintf(
+				"ImplicitMeta policy %s/%s references sub-policy %s, which no sub-group of %s defines",
+				groupPath, name, implicitMeta.SubPolicy, groupPath))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(group.Groups)) {
+		warnings = append(warnings, unsatisfiedImplicitMetaPolicies(groupPath+"/"+name, group.Groups[name])...)
+	}
+	return warnings
+}
@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestUnsatisfiedImplicitMetaPolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all sub-policies defined", func(t *testing.T) {
+		t.Parallel()
+		profile := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		channelGroup, err := NewChannelGroup(profile)
+		require.NoError(t, err)
+		require.Empty(t, unsatisfiedImplicitMetaPolicies("/Channel", channelGroup))
+	})
+
+	t.Run("empty application group", func(t *testing.T) {
+		t.Parallel()
+		profile := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		profile.Application.Organizations = nil
+		channelGroup, err := NewChannelGroup(profile)
+		require.NoError(t, err)
+		require.Contains(t, unsatisfiedImplicitMetaPolicies("/Channel", channelGroup),
+			"ImplicitMeta policy /Channel/Application/Admins references sub-policy Admins, "+
+				"which no sub-group of /Channel/Application defines")
+	})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/protolator"
+	"github.com/hyperledger/fabric-x-common/protoutil"
+)
+
+// InspectBlockTolerant is like InspectBlock, but tolerates config values that fail to decode.
+// Such values are left out of the decoded contents, and an error is returned for each of them,
+// so partially corrupt config blocks can still be inspected.
+func InspectBlockTolerant(path string) (map[string]any, []error) {
+	block, err := protoutil.ReadBlockFromFile(path)
+	if err != nil {
+		return nil, []error{err}
+	}
+	decoded, err := decodeBlock(block)
+	if err == nil {
+		return decoded, nil
+	}
+
+	block, valueErrs, err := pruneUndecodableValues(block)
+	if err != nil {
+		return nil, []error{err}
+	}
+	decoded, err = decodeBlock(block)
+	if err != nil {
+		return nil, append(valueErrs, err)
+	}
+	return decoded, valueErrs
+}
+
+// pruneUndecodableValues returns a copy of the config block without the config values that fail to decode,
+// and an error for each of them.
+func pruneUndecodableValues(block *cb.Block) (*cb.Block, []error, error) {
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	configEnvelope, err := protoutil.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if configEnvelope.Config == nil || configEnvelope.Config.ChannelGroup == nil {
+		return nil, nil, errors.New("the config envelope has no channel group")
+	}
+
+	valueErrs := pruneGroup(configEnvelope.Config.ChannelGroup, []string{channelconfig.ChannelGroupKey})
+
+	payload.Data, err = proto.Marshal(configEnvelope)
+	if err != nil {
+		return nil, nil, err
+	}
+	envelope.Payload, err = proto.Marshal(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	pruned := proto.CloneOf(block)
+	pruned.Data.Data[0], err = proto.Marshal(envelope)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pruned, valueErrs, nil
+}
+
+// pruneGroup removes the values of the group, and of its sub-groups, that fail to decode.
+// The groupPath holds the keys from the channel group down to the group.
+func pruneGroup(group *cb.ConfigGroup, groupPath []string) []error {
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(group.Values)) {
+		err := decodeValue(groupPath, key, group.Values[key])
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "value %s/%s", strings.Join(groupPath, "/"), key))
+			delete(group.Values, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(group.Groups)) {
+		errs = append(errs, pruneGroup(group.Groups[key], append(slices.Clone(groupPath), key))...)
+	}
+	return errs
+}
+
+// decodeValue decodes a config value in the context of its group, which determines its type.
+func decodeValue(groupPath []string, key string, value *cb.ConfigValue) error {
+	group := &cb.ConfigGroup{Values: map[string]*cb.ConfigValue{key: value}}
+	for i := len(groupPath) - 1; i > 0; i-- {
+		group = &cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{groupPath[i]: group}}
+	}
+	return protolator.DeepMarshalJSON(io.Discard, &cb.Config{ChannelGroup: group})
+}
@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"path/filepath"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+	"github.com/hyperledger/fabric-x-common/protoutil"
+)
+
+func TestInspectBlockTolerant(t *testing.T) {
+	t.Parallel()
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	block, err := GetOutputBlock(config, "foo")
+	require.NoError(t, err)
+
+	healthyDest := filepath.Join(t.TempDir(), "healthy")
+	require.NoError(t, WriteOutputBlock(block, healthyDest))
+	decoded, errs := InspectBlockTolerant(healthyDest)
+	require.Empty(t, errs)
+	require.NotNil(t, decoded)
+
+	// Corrupt the BatchSize value of the orderer group.
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	require.NoError(t, err)
+	payload, err := protoutil.UnmarshalPayload(envelope.Payload)
+	require.NoError(t, err)
+	configEnvelope, err := protoutil.UnmarshalConfigEnvelope(payload.Data)
+	require.NoError(t, err)
+	ordererGroup := configEnvelope.Config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	ordererGroup.Values[channelconfig.BatchSizeKey] = &cb.ConfigValue{Value: []byte{0xff, 0xff}}
+	payload.Data = protoutil.MarshalOrPanic(configEnvelope)
+	envelope.Payload = protoutil.MarshalOrPanic(payload)
+	block.Data.Data[0] = protoutil.MarshalOrPanic(envelope)
+	corruptDest := filepath.Join(t.TempDir(), "corrupt")
+	require.NoError(t, WriteOutputBlock(block, corruptDest))
+
+	_, err = InspectBlock(corruptDest)
+	require.Error(t, err)
+
+	decoded, errs = InspectBlockTolerant(corruptDest)
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "value Channel/Orderer/BatchSize")
+	channelGroup := lookup(t, decoded, "data", "data", 0, "payload", "data", "config", "channel_group")
+	ordererValues := lookup(t, channelGroup, "groups", "Orderer", "values")
+	require.NotContains(t, ordererValues, channelconfig.BatchSizeKey)
+	require.Equal(t, "solo", lookup(t, ordererValues, "ConsensusType", "value", "type"))
+
+	_, errs = InspectBlockTolerant(filepath.Join(t.TempDir(), "missing"))
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "could not read block")
+}
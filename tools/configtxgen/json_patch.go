@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+// ApplyJSONPatch applies an RFC 6902 JSON patch to a copy of the profile, and returns the patched copy.
+// The patch addresses the JSON encoding of the profile, in which the fields are named as in configtx.yaml,
+// and durations are in nanoseconds, e.g.,
+//
+//	[{"op": "replace", "path": "/Orderer/BatchTimeout", "value": 5000000000}]
+//
+// It fails if an operation does not apply, or if the patched profile can no longer be encoded.
+func ApplyJSONPatch(p *Profile, patch []byte) (*Profile, error) {
+	if p == nil {
+		return nil, errors.New("profile must not be nil")
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not encode the profile")
+	}
+	raw, err = applyJSONPatch(raw, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	patched := &Profile{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(patched); err != nil {
+		return nil, errors.Wrap(err, "the patched profile is invalid")
+	}
+	if _, err = NewChannelGroup(patched); err != nil {
+		return nil, errors.Wrap(err, "the patched profile is invalid")
+	}
+	return patched, nil
+}
+
+// applyJSONPatch applies an RFC 6902 JSON patch to a JSON document, one operation at a time.
+func applyJSONPatch(doc, patch []byte) ([]byte, error) {
+	ops, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid JSON patch")
+	}
+	options := jsonpatch.NewApplyOptions()
+	// Negative array indices are not part of RFC 6902.
+	options.SupportNegativeIndices = false
+	for i, op := range ops {
+		if err = checkMoveOperation(op); err == nil {
+			doc, err = jsonpatch.Patch{op}.ApplyWithOptions(doc, options)
+		}
+		if err != nil {
+			path, _ := op.Path()
+			return nil, errors.Wrapf(err, "operation %d (%s %s) failed", i, op.Kind(), path)
+		}
+	}
+	return doc, nil
+}
+
+// checkMoveOperation rejects moving a value into one of its children, which RFC 6902 forbids.
+func checkMoveOperation(op jsonpatch.Operation) error {
+	if op.Kind() != "move" {
+		return nil
+	}
+	from, err := op.From()
+	if err != nil {
+		return err
+	}
+	path, err := op.Path()
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(path, from+"/") {
+		return errors.Newf("cannot move '%s' into its own child '%s'", from, path)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("patches the batch timeout", func(t *testing.T) {
+		t.Parallel()
+		p := Load(SampleAppChannelSmartBftProfile, configtest.GetDevConfigDir())
+		original := p.Orderer.BatchTimeout
+		require.NotEqual(t, 5*time.Second, original)
+
+		patched, err := ApplyJSONPatch(p, []byte(`[
+			{"op": "test", "path": "/Orderer/OrdererType", "value": "BFT"},
+			{"op": "replace", "path": "/Orderer/BatchTimeout", "value": 5000000000}
+		]`))
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Second, patched.Orderer.BatchTimeout)
+		require.Equal(t, p.Orderer.BatchSize, patched.Orderer.BatchSize)
+		require.Equal(t, original, p.Orderer.BatchTimeout, "the original profile is left untouched")
+	})
+
+	for _, tc := range []struct {
+		name     string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "malformed patch",
+			patch:    `{"op": "remove"}`,
+			expected: "invalid JSON patch",
+		},
+		{
+			name:     "missing member",
+			patch:    `[{"op": "replace", "path": "/Orderer/Bogus", "value": 1}]`,
+			expected: "operation 0 (replace /Orderer/Bogus) failed: replace operation does not apply: doc is missing key",
+		},
+		{
+			name:     "out of range index",
+			patch:    `[{"op": "remove", "path": "/Orderer/Organizations/100"}]`,
+			expected: "operation 0 (remove /Orderer/Organizations/100) failed",
+		},
+		{
+			name:     "failed test",
+			patch:    `[{"op": "test", "path": "/Orderer/OrdererType", "value": "solo"}]`,
+			expected: "operation 0 (test /Orderer/OrdererType) failed: testing value /Orderer/OrdererType failed",
+		},
+		{
+			name:     "unknown operation",
+			patch:    `[{"op": "merge", "path": "/Orderer"}]`,
+			expected: "invalid JSON patch: invalid operation {\"op\":\"merge\",\"path\":\"/Orderer\"}: unsupported operation",
+		},
+		{
+			name:     "move into its own child",
+			patch:    `[{"op": "move", "from": "/Orderer", "path": "/Orderer/Inner"}]`,
+			expected: "operation 0 (move /Orderer/Inner) failed: cannot move '/Orderer' into its own child '/Orderer/Inner'",
+		},
+		{
+			name:     "negative index",
+			patch:    `[{"op": "remove", "path": "/Orderer/Organizations/-1"}]`,
+			expected: "operation 0 (remove /Orderer/Organizations/-1) failed",
+		},
+		{
+			name:     "unknown field",
+			patch:    `[{"op": "add", "path": "/Orderer/Bogus", "value": 1}]`,
+			expected: "the patched profile is invalid: json: unknown field \"Bogus\"",
+		},
+		{
+			name:     "wrong type",
+			patch:    `[{"op": "replace", "path": "/Orderer/BatchTimeout", "value": "5s"}]`,
+			expected: "the patched profile is invalid: json: cannot unmarshal string",
+		},
+		{
+			name:     "unencodable profile",
+			patch:    `[{"op": "replace", "path": "/Orderer/OrdererType", "value": "bogus"}]`,
+			expected: "the patched profile is invalid",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := Load(SampleAppChannelSmartBftProfile, configtest.GetDevConfigDir())
+			_, err := ApplyJSONPatch(p, []byte(tc.patch))
+			require.ErrorContains(t, err, tc.expected)
+		})
+	}
+
+	t.Run("requires a profile", func(t *testing.T) {
+		t.Parallel()
+		_, err := ApplyJSONPatch(nil, []byte(`[]`))
+		require.EqualError(t, err, "profile must not be nil")
+	})
+}
+
+func TestApplyJSONPatchRFC6902Examples(t *testing.T) {
+	t.Parallel()
+	// The examples of RFC 6902, appendix A.
+	for _, tc := range []struct {
+		name     string
+		doc      string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "A.1 adding an object member",
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			expected: `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:     "A.2 adding an array element",
+			doc:      `{"foo": ["bar", "baz"]}`,
+			patch:    `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			expected: `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:     "A.3 removing an object member",
+			doc:      `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "remove", "path": "/baz"}]`,
+			expected: `{"foo": "bar"}`,
+		},
+		{
+			name:     "A.4 removing an array element",
+			doc:      `{"foo": ["bar", "qux", "baz"]}`,
+			patch:    `[{"op": "remove", "path": "/foo/1"}]`,
+			expected: `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:     "A.5 replacing a value",
+			doc:      `{"baz": "qux", "foo": "bar"}`,
+			patch:    `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			expected: `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:     "A.6 moving a value",
+			doc:      `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch:    `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			expected: `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:     "A.7 moving an array element",
+			doc:      `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch:    `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			expected: `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name: "A.8 testing a value: success",
+			doc:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[
+				{"op": "test", "path": "/baz", "value": "qux"},
+				{"op": "test", "path": "/foo/1", "value": 2}
+			]`,
+			expected: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:  "A.9 testing a value: error",
+			doc:   `{"baz": "qux"}`,
+			patch: `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+		},
+		{
+			name:     "A.10 adding a nested member object",
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			expected: `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:     "A.11 ignoring unrecognized elements",
+			doc:      `{"foo": "bar"}`,
+			patch:    `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			expected: `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:  "A.12 adding to a nonexistent target",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+		},
+		{
+			name:  "A.13 invalid JSON patch document",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "op": "remove"}]`,
+		},
+		{
+			name:     "A.14 escape ordering",
+			doc:      `{"/": 9, "~1": 10}`,
+			patch:    `[{"op": "test", "path": "/~01", "value": 10}]`,
+			expected: `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:  "A.15 comparing strings and numbers",
+			doc:   `{"/": 9, "~1": 10}`,
+			patch: `[{"op": "test", "path": "/~01", "value": "10"}]`,
+		},
+		{
+			name:     "A.16 adding an array value",
+			doc:      `{"foo": ["bar"]}`,
+			patch:    `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			expected: `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			patched, err := applyJSONPatch([]byte(tc.doc), []byte(tc.patch))
+			if tc.expected == "" {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.JSONEq(t, tc.expected, string(patched))
+		})
+	}
+}
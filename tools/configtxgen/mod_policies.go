@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+)
+
+// applyModPolicies overrides the mod_policy of the config elements addressed by the keys of modPolicies.
+// The last element of a path is resolved as a group first, and as a value otherwise.
+func applyModPolicies(channelGroup *cb.ConfigGroup, modPolicies map[string]string) error {
+	for _, elementPath := range slices.Sorted(maps.Keys(modPolicies)) {
+		err := applyModPolicy(channelGroup, elementPath, modPolicies[elementPath])
+		if err != nil {
+			return errors.WithMessagef(err, "invalid mod_policy for '%s'", elementPath)
+		}
+	}
+	return nil
+}
+
+func applyModPolicy(channelGroup *cb.ConfigGroup, elementPath, modPolicy string) error {
+	segments := strings.Split(elementPath, "/")
+	parent, err := findConfigGroup(channelGroup, segments[:len(segments)-1])
+	if err != nil {
+		return err
+	}
+
+	name := segments[len(segments)-1]
+	if group, ok := parent.Groups[name]; ok {
+		// The mod_policy of a group is relative to the group itself.
+		if err = checkPolicyExists(channelGroup, group, modPolicy); err != nil {
+			return err
+		}
+		group.ModPolicy = modPolicy
+		return nil
+	}
+	if value, ok := parent.Values[name]; ok {
+		// The mod_policy of a value is relative to the group containing it.
+		if err = checkPolicyExists(channelGroup, parent, modPolicy); err != nil {
+			return err
+		}
+		value.ModPolicy = modPolicy
+		return nil
+	}
+	return errors.Newf("config group or value '%s' not found", name)
+}
+
+func findConfigGroup(root *cb.ConfigGroup, segments []string) (*cb.ConfigGroup, error) {
+	group := root
+	for _, segment := range segments {
+		child, ok := group.Groups[segment]
+		if !ok {
+			return nil, errors.Newf("config group '%s' not found", segment)
+		}
+		group = child
+	}
+	return group, nil
+}
+
+// checkPolicyExists checks that a policy reference resolves, either as an absolute path
+// from the channel group, or relative to the given group.
+func checkPolicyExists(channelGroup, group *cb.ConfigGroup, policyRef string) error {
+	policyName := policyRef
+	if absPath, ok := strings.CutPrefix(policyRef, "/"+channelconfig.RootGroupKey+"/"); ok {
+		segments := strings.Split(absPath, "/")
+		var err error
+		group, err = findConfigGroup(channelGroup, segments[:len(segments)-1])
+		if err != nil {
+			return errors.WithMessagef(err, "policy '%s'", policyRef)
+		}
+		policyName = segments[len(segments)-1]
+	}
+	if _, ok := group.Policies[policyName]; !ok {
+		return errors.Newf("policy '%s' does not exist", policyRef)
+	}
+	return nil
+}
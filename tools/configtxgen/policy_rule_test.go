@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePolicyRule(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, ValidatePolicyRule(SignaturePolicyType, "OutOf(2, 'Org1.member', 'Org2.member')"))
+	require.NoError(t, ValidatePolicyRule(ImplicitMetaPolicyType, "MAJORITY Admins"))
+
+	for _, tc := range []struct {
+		typ      string
+		rule     string
+		expected string
+	}{
+		{
+			typ:      SignaturePolicyType,
+			rule:     "OutOf(2, 'Org1.member'",
+			expected: "invalid signature policy rule 'OutOf(2, 'Org1.member''",
+		},
+		{
+			typ:      ImplicitMetaPolicyType,
+			rule:     "SOME Admins",
+			expected: "invalid implicit meta policy rule 'SOME Admins'",
+		},
+		{
+			typ:      "Bogus",
+			rule:     "ANY Admins",
+			expected: "unknown policy type: Bogus",
+		},
+	} {
+		t.Run(tc.rule, func(t *testing.T) {
+			t.Parallel()
+			require.ErrorContains(t, ValidatePolicyRule(tc.typ, tc.rule), tc.expected)
+		})
+	}
+}
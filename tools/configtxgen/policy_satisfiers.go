@@ -0,0 +1,116 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
+	mspproto "github.com/hyperledger/fabric-protos-go-apiv2/msp"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger/fabric-x-common/protoutil"
+)
+
+// PolicySatisfiers statically determines the MSP IDs of the organizations whose identities could take part
+// in satisfying a policy of the profile. ImplicitMeta policies are resolved through the sub-policies of
+// the sub-groups, down to signature policies, whose principals name the organizations.
+// The policy path is relative to the channel group, as in DescribePolicies, e.g., "Application/Admins".
+func PolicySatisfiers(p *Profile, policyPath string) ([]string, error) {
+	channelGroup, err := NewChannelGroup(p)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not create the channel group of the profile")
+	}
+	segments := strings.Split(policyPath, "/")
+	group, err := findConfigGroup(channelGroup, segments[:len(segments)-1])
+	if err != nil {
+		return nil, errors.WithMessagef(err, "policy '%s'", policyPath)
+	}
+	mspIDs := make(map[string]struct{})
+	if err = collectPolicySatisfiers(group, segments[len(segments)-1], mspIDs); err != nil {
+		return nil, errors.WithMessagef(err, "policy '%s'", policyPath)
+	}
+	return slices.Sorted(maps.Keys(mspIDs)), nil
+}
+
+func collectPolicySatisfiers(group *cb.ConfigGroup, policyName string, mspIDs map[string]struct{}) error {
+	configPolicy, ok := group.Policies[policyName]
+	if !ok {
+		return errors.Newf("policy '%s' not found", policyName)
+	}
+	policy := configPolicy.GetPolicy()
+	switch cb.Policy_PolicyType(policy.GetType()) {
+	case cb.Policy_IMPLICIT_META:
+		implicitMeta := &cb.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Value, implicitMeta); err != nil {
+			return errors.Wrapf(err, "could not unmarshal implicit meta policy '%s'", policyName)
+		}
+		for _, name := range slices.Sorted(maps.Keys(group.Groups)) {
+			subGroup := group.Groups[name]
+			if _, ok := subGroup.Policies[implicitMeta.SubPolicy]; !ok {
+				continue
+			}
+			if err := collectPolicySatisfiers(subGroup, implicitMeta.SubPolicy, mspIDs); err != nil {
+				return errors.WithMessagef(err, "group '%s'", name)
+			}
+		}
+		return nil
+	case cb.Policy_SIGNATURE:
+		envelope := &cb.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Value, envelope); err != nil {
+			return errors.Wrapf(err, "could not unmarshal signature policy '%s'", policyName)
+		}
+		for _, principal := range envelope.Identities {
+			if err := collectPrincipalMSPIDs(principal, mspIDs); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.Newf("policy '%s' has unsupported type %d", policyName, policy.GetType())
+	}
+}
+
+// collectPrincipalMSPIDs adds the IDs of the MSPs a principal refers to.
+func collectPrincipalMSPIDs(principal *mspproto.MSPPrincipal, mspIDs map[string]struct{}) error {
+	switch principal.PrincipalClassification {
+	case mspproto.MSPPrincipal_ROLE:
+		role := &mspproto.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return errors.Wrap(err, "could not unmarshal MSPRole")
+		}
+		mspIDs[role.MspIdentifier] = struct{}{}
+	case mspproto.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mspproto.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, ou); err != nil {
+			return errors.Wrap(err, "could not unmarshal OrganizationUnit")
+		}
+		mspIDs[ou.MspIdentifier] = struct{}{}
+	case mspproto.MSPPrincipal_IDENTITY:
+		id, err := protoutil.UnmarshalIdentity(principal.Principal)
+		if err != nil {
+			return err
+		}
+		mspIDs[id.MspId] = struct{}{}
+	case mspproto.MSPPrincipal_COMBINED:
+		combined := &mspproto.CombinedPrincipal{}
+		if err := proto.Unmarshal(principal.Principal, combined); err != nil {
+			return errors.Wrap(err, "could not unmarshal CombinedPrincipal")
+		}
+		for _, p := range combined.Principals {
+			if err := collectPrincipalMSPIDs(p, mspIDs); err != nil {
+				return err
+			}
+		}
+	default:
+		// Anonymity principals do not refer to an MSP.
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestPolicySatisfiers(t *testing.T) {
+	t.Parallel()
+	profile := Load(TwoOrgsSampleFabricX, configtest.GetDevConfigDir())
+	profile.Orderer.Arma.Path = filepath.Join(configtest.GetDevConfigDir(), "arma_shared_config.pbbin")
+
+	for policyPath, expected := range map[string][]string{
+		"Application/Admins":      {"Org1", "Org2"},
+		"Admins":                  {"Org1", "Org2"},
+		"Application/Org1/Admins": {"Org1"},
+	} {
+		satisfiers, err := PolicySatisfiers(profile, policyPath)
+		require.NoError(t, err, policyPath)
+		require.Equal(t, expected, satisfiers, policyPath)
+	}
+
+	_, err := PolicySatisfiers(profile, "Application/Auditors")
+	require.EqualError(t, err, "policy 'Application/Auditors': policy 'Auditors' not found")
+	_, err = PolicySatisfiers(profile, "Unknown/Admins")
+	require.EqualError(t, err, "policy 'Unknown/Admins': config group 'Unknown' not found")
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/hyperledger/fabric-x-common/api/types"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	ordererEndpointType = reflect.TypeFor[types.OrdererEndpoint]()
+)
+
+// ProfileToYAML renders the profile as an entry of the Profiles section of configtx.yaml,
+// including its policies and capabilities, such that loading a configtx.yaml that lists it reproduces the profile.
+// Paths, e.g., of the MSP directories, are rendered as they are in the profile, i.e., absolute once loaded.
+func ProfileToYAML(p *Profile) ([]byte, error) {
+	if p == nil {
+		return nil, errors.New("profile must not be nil")
+	}
+	if p.Orderer != nil && p.Orderer.Arma != nil && len(p.Orderer.Arma.Bytes) > 0 {
+		return nil, errors.New("inline Arma consensus metadata cannot be rendered to YAML")
+	}
+	node, err := yamlNode(reflect.ValueOf(p))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not render the profile")
+	}
+	out, err := yaml.Marshal(node)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not render the profile")
+	}
+	return out, nil
+}
+
+// yamlNode renders a value the way configtx.yaml spells it, which yaml.Marshal does not:
+// nil pointers, slices and maps are omitted, so that they remain unset once loaded,
+// byte slices (which hold paths) are rendered as strings, durations and orderer endpoints in their string form,
+// and the fields of structs without YAML tags, e.g., of protos, by their Go names.
+func yamlNode(v reflect.Value) (*yaml.Node, error) {
+	switch {
+	case v.Type() == durationType:
+		return scalarNode(time.Duration(v.Int()).String()), nil
+	case v.Type() == reflect.PointerTo(ordererEndpointType):
+		return scalarNode(v.Interface().(*types.OrdererEndpoint).String()), nil //nolint:forcetypeassert
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+		}
+		return yamlNode(v.Elem())
+	case reflect.Struct:
+		return structNode(v)
+	case reflect.Map:
+		return mapNode(v)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return scalarNode(string(v.Bytes())), nil
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := range v.Len() {
+			item, err := yamlNode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, item)
+		}
+		return node, nil
+	default:
+		node := &yaml.Node{}
+		return node, node.Encode(v.Interface())
+	}
+}
+
+func structNode(v reflect.Value) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		value := v.Field(i)
+		if isUnset(value) || (opts == "omitempty" && value.IsZero()) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		valueNode, err := yamlNode(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "field %s", field.Name)
+		}
+		node.Content = append(node.Content, scalarNode(name), valueNode)
+	}
+	return node, nil
+}
+
+func mapNode(v reflect.Value) (*yaml.Node, error) {
+	keys := v.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	})
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		keyNode, err := yamlNode(key)
+		if err != nil {
+			return nil, err
+		}
+		valueNode, err := yamlNode(v.MapIndex(key))
+		if err != nil {
+			return nil, errors.Wrapf(err, "key %v", key.Interface())
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+	return node, nil
+}
+
+func isUnset(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
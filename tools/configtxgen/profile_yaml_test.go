@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestProfileToYAML(t *testing.T) {
+	t.Parallel()
+
+	for _, profileName := range []string{
+		SampleAppChannelInsecureSoloProfile,
+		SampleAppChannelEtcdRaftProfile,
+		SampleAppChannelSmartBftProfile,
+		SampleSingleMSPSoloProfile,
+		SampleFabricX,
+	} {
+		t.Run(profileName, func(t *testing.T) {
+			t.Parallel()
+			p := Load(profileName, configtest.GetDevConfigDir())
+			out, err := ProfileToYAML(p)
+			require.NoError(t, err)
+
+			reloaded := loadRenderedProfile(t, out)
+			// The profiles hold protos, which are compared by their encoding.
+			expected, err := json.Marshal(p)
+			require.NoError(t, err)
+			actual, err := json.Marshal(reloaded)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), string(actual))
+		})
+	}
+
+	t.Run("inline Arma metadata", func(t *testing.T) {
+		t.Parallel()
+		p := Load(SampleFabricX, configtest.GetDevConfigDir())
+		p.Orderer.Arma = &ConsensusMetadata{Bytes: []byte("metadata")}
+		_, err := ProfileToYAML(p)
+		require.EqualError(t, err, "inline Arma consensus metadata cannot be rendered to YAML")
+	})
+
+	t.Run("requires a profile", func(t *testing.T) {
+		t.Parallel()
+		_, err := ProfileToYAML(nil)
+		require.EqualError(t, err, "profile must not be nil")
+	})
+}
+
+// loadRenderedProfile writes a configtx.yaml that lists the rendered profile, and loads it back.
+func loadRenderedProfile(t *testing.T, rendered []byte) *Profile {
+	t.Helper()
+	var profile any
+	require.NoError(t, yaml.Unmarshal(rendered, &profile))
+	configtx, err := yaml.Marshal(map[string]any{"Profiles": map[string]any{"Rendered": profile}})
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "configtx.yaml"), configtx, 0o600))
+	return Load("Rendered", dir)
+}
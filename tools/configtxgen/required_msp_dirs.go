@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"slices"
+)
+
+// RequiredMSPDirs returns the MSP directories that must exist to generate the config of a profile,
+// namely the MSPDir of each organization the profile references, sorted and without repetitions.
+// Organizations skipped as foreign are omitted, as their MSPs are not read.
+func RequiredMSPDirs(p *Profile) []string {
+	if p == nil {
+		return nil
+	}
+	var dirs []string
+	if p.Orderer != nil {
+		dirs = appendMSPDirs(dirs, p.Orderer.Organizations)
+	}
+	if p.Application != nil {
+		dirs = appendMSPDirs(dirs, p.Application.Organizations)
+	}
+	for _, consortium := range p.Consortiums {
+		dirs = appendMSPDirs(dirs, consortium.Organizations)
+	}
+	slices.Sort(dirs)
+	return slices.Compact(dirs)
+}
+
+func appendMSPDirs(dirs []string, orgs []*Organization) []string {
+	for _, org := range orgs {
+		if org.SkipAsForeign || org.MSPDir == "" {
+			continue
+		}
+		dirs = append(dirs, org.MSPDir)
+	}
+	return dirs
+}
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestRequiredMSPDirs(t *testing.T) {
+	t.Parallel()
+	// The sample organization is referenced by the orderer, the application and the consortium.
+	profile := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+	require.Equal(t, []string{configtest.GetDevMspDir()}, RequiredMSPDirs(profile))
+
+	profile = &Profile{
+		Orderer: &Orderer{Organizations: []*Organization{
+			{Name: "OrdererOrg", MSPDir: "/crypto/orderer-org/msp"},
+		}},
+		Application: &Application{Organizations: []*Organization{
+			{Name: "Org2", MSPDir: "/crypto/org2/msp"},
+			{Name: "Org1", MSPDir: "/crypto/org1/msp"},
+			{Name: "ForeignOrg", MSPDir: "/crypto/foreign-org/msp", SkipAsForeign: true},
+		}},
+	}
+	require.Equal(t, []string{
+		"/crypto/orderer-org/msp",
+		"/crypto/org1/msp",
+		"/crypto/org2/msp",
+	}, RequiredMSPDirs(profile))
+
+	require.Empty(t, RequiredMSPDirs(nil))
+}
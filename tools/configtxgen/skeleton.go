@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/common/policies"
+	"github.com/hyperledger/fabric-x-common/msp"
+)
+
+// endorsementPolicyKey is the name of the organizations' and application's endorsement policy.
+const endorsementPolicyKey = "Endorsement"
+
+// NewSkeletonProfile returns a minimal application channel profile for the given organization names.
+// Each organization uses its name as MSP ID, and is given the standard signature policies.
+// The application, orderer and channel groups are given the standard implicit meta policies.
+// The orderer section uses the default solo consensus without any orderer organization.
+// The organizations' MSPDir must be supplied before the profile can be used to generate a block.
+func NewSkeletonProfile(orgs []string) *Profile {
+	organizations := make([]*Organization, len(orgs))
+	for i, name := range orgs {
+		organizations[i] = &Organization{
+			Name:           name,
+			ID:             name,
+			MSPType:        msp.ProviderTypeToString(msp.FABRIC),
+			AdminPrincipal: AdminRoleAdminPrincipal,
+			Policies: map[string]*Policy{
+				channelconfig.ReadersPolicyKey: signaturePolicy("OR('%s.member')", name),
+				channelconfig.WritersPolicyKey: signaturePolicy("OR('%s.member')", name),
+				channelconfig.AdminsPolicyKey:  signaturePolicy("OR('%s.admin')", name),
+				endorsementPolicyKey:           signaturePolicy("OR('%s.member')", name),
+			},
+		}
+	}
+
+	orderer := genesisOrdererDefaults()
+	orderer.Policies = standardPolicies()
+	orderer.Policies[policies.BlockValidationPolicyKey] = implicitMetaPolicy("ANY Writers")
+
+	application := &Application{
+		Organizations: organizations,
+		Policies:      standardPolicies(),
+	}
+	application.Policies[endorsementPolicyKey] = implicitMetaPolicy("MAJORITY Endorsement")
+
+	return &Profile{
+		Application: application,
+		Orderer:     &orderer,
+		Policies:    standardPolicies(),
+	}
+}
+
+// standardPolicies returns the standard Readers, Writers, and Admins implicit meta policies.
+func standardPolicies() map[string]*Policy {
+	return map[string]*Policy{
+		channelconfig.ReadersPolicyKey: implicitMetaPolicy("ANY Readers"),
+		channelconfig.WritersPolicyKey: implicitMetaPolicy("ANY Writers"),
+		channelconfig.AdminsPolicyKey:  implicitMetaPolicy("MAJORITY Admins"),
+	}
+}
+
+func implicitMetaPolicy(rule string) *Policy {
+	return &Policy{Type: ImplicitMetaPolicyType, Rule: rule}
+}
+
+func signaturePolicy(ruleFormat, orgName string) *Policy {
+	return &Policy{Type: SignaturePolicyType, Rule: fmt.Sprintf(ruleFormat, orgName)}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-lib-go/bccsp/factory"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+	"github.com/hyperledger/fabric-x-common/protoutil"
+)
+
+func TestNewSkeletonProfile(t *testing.T) {
+	t.Parallel()
+	orgs := []string{"Org1", "Org2"}
+	profile := NewSkeletonProfile(orgs)
+	require.NotNil(t, profile.Application)
+	require.NotNil(t, profile.Orderer)
+	require.Len(t, profile.Application.Organizations, len(orgs))
+
+	// The MSP directories are not known to the skeleton.
+	_, err := GetOutputBlock(profile, "foo")
+	require.ErrorContains(t, err, "Error loading MSP configuration for org Org1")
+
+	for _, org := range profile.Application.Organizations {
+		org.MSPDir = filepath.Join(configtest.GetDevConfigDir(), "crypto", org.Name, "msp")
+	}
+	block, err := GetOutputBlock(profile, "foo")
+	require.NoError(t, err)
+
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromEnvelope(envelope, factory.GetDefault())
+	require.NoError(t, err)
+
+	ac, ok := bundle.ApplicationConfig()
+	require.True(t, ok)
+	require.Len(t, ac.Organizations(), len(orgs))
+	for _, name := range orgs {
+		require.Equal(t, name, ac.Organizations()[name].MSPID())
+	}
+	_, ok = bundle.OrdererConfig()
+	require.True(t, ok)
+}
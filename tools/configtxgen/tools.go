@@ -7,25 +7,61 @@ SPDX-License-Identifier: Apache-2.0
 package configtxgen
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/hyperledger/fabric-lib-go/common/flogging"
 	"github.com/hyperledger/fabric-protos-go-apiv2/common"
 	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
 
+	"github.com/hyperledger/fabric-x-common/common/capabilities"
+	"github.com/hyperledger/fabric-x-common/common/configtx"
+	"github.com/hyperledger/fabric-x-common/common/genesis"
 	"github.com/hyperledger/fabric-x-common/protolator"
 	"github.com/hyperledger/fabric-x-common/protolator/protoext/ordererext"
 	"github.com/hyperledger/fabric-x-common/protolator/protoext/peerext"
 	"github.com/hyperledger/fabric-x-common/protoutil"
+	"github.com/hyperledger/fabric-x-common/protoutil/identity"
 )
 
 var logger = flogging.MustGetLogger("common.tools.configtxgen")
 
 // GetOutputBlock generates a genesis block.
 func GetOutputBlock(config *Profile, channelID string) (*cb.Block, error) {
+	pgen, err := newOutputBootstrapper(config)
+	if err != nil {
+		return nil, err
+	}
+	genesisBlock := pgen.GenesisBlockForChannel(channelID)
+	return genesisBlock, nil
+}
+
+// GenesisBlockHash returns the genesis block of the profile for a channel, along with its header hash.
+// The block is generated with the given timestamp and no randomness, so the hash is reproducible from the same
+// profile, channel ID, and timestamp. It only matches the genesis blocks generated with the same timestamp,
+// e.g., the returned block, which may be written with WriteOutputBlock, but not the blocks of GetOutputBlock,
+// which have the current time and a random nonce.
+func GenesisBlockHash(config *Profile, channelID string, timestamp time.Time) (*cb.Block, []byte, error) {
+	pgen, err := newOutputBootstrapper(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	genesisBlock := pgen.GenesisBlockForChannelAt(channelID, timestamp)
+	return genesisBlock, protoutil.BlockHeaderHash(genesisBlock.Header), nil
+}
+
+// newOutputBootstrapper checks that the profile can generate a genesis block, and returns its bootstrapper.
+func newOutputBootstrapper(config *Profile) (*Bootstrapper, error) {
+	if config.OrdererOnly && config.Application != nil {
+		return nil, errors.New("refusing to generate orderer-only channel block which has an application section")
+	}
 	pgen, err := NewBootstrapper(config)
 	if err != nil {
 		return nil, errors.WithMessage(err, "could not create bootstrapper")
@@ -36,14 +72,58 @@ func GetOutputBlock(config *Profile, channelID string) (*cb.Block, error) {
 	}
 	if config.Consortiums != nil {
 		logger.Error("Warning: 'Consortiums' should be nil since system channel is no longer supported in Fabric v3.x")
+	} else if config.OrdererOnly {
+		logger.Info("Creating orderer-only channel genesis block")
 	} else {
 		if config.Application == nil {
 			return nil, errors.New("refusing to generate application channel block which is missing application section")
 		}
 		logger.Info("Creating application channel genesis block")
 	}
-	genesisBlock := pgen.GenesisBlockForChannel(channelID)
-	return genesisBlock, nil
+	return pgen, nil
+}
+
+// DeriveCapabilities enables the capabilities required by the orderer type of the profile, namely the V3_0
+// channel capability for BFT. As channel capabilities require orderer support, the V2_0 orderer capability
+// is enabled too, if the orderer has no capabilities.
+func DeriveCapabilities(config *Profile) {
+	if config.Orderer == nil || config.Orderer.OrdererType != ConsensusTypeBFT {
+		return
+	}
+	if !config.Capabilities[capabilities.ChannelV3_0] {
+		logger.Infof("Enabling the %s channel capability, required by the %s orderer type",
+			capabilities.ChannelV3_0, ConsensusTypeBFT)
+		config.Capabilities = withCapability(config.Capabilities, capabilities.ChannelV3_0)
+	}
+	if len(config.Orderer.Capabilities) == 0 {
+		logger.Infof("Enabling the %s orderer capability, required by the channel capabilities",
+			capabilities.OrdererV2_0)
+		config.Orderer.Capabilities = withCapability(config.Orderer.Capabilities, capabilities.OrdererV2_0)
+	}
+}
+
+// withCapability returns a copy of the capabilities with the given one enabled.
+// The capabilities are copied, as they may be shared with other profiles of the same configuration.
+func withCapability(caps map[string]bool, capability string) map[string]bool {
+	caps = maps.Clone(caps)
+	if caps == nil {
+		caps = map[string]bool{}
+	}
+	caps[capability] = true
+	return caps
+}
+
+// BlockFromChannelGroup wraps a channel config group into a genesis block for the given channel.
+// It allows generating a block from a config group that was constructed programmatically,
+// e.g., with NewChannelGroup, rather than from a profile.
+func BlockFromChannelGroup(channelID string, group *cb.ConfigGroup) (*cb.Block, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID must not be empty")
+	}
+	if group == nil {
+		return nil, errors.New("channel group must not be nil")
+	}
+	return genesis.NewFactoryImpl(group).Block(channelID), nil
 }
 
 // WriteOutputBlock writes a block to a file.
@@ -57,6 +137,9 @@ func WriteOutputBlock(block *cb.Block, outputBlock string) error {
 
 // DoOutputBlock generates a genesis block and writes it to a file.
 func DoOutputBlock(config *Profile, channelID, outputBlock string) error {
+	if err := ValidateChannelID(channelID); err != nil {
+		return err
+	}
 	genesisBlock, err := GetOutputBlock(config, channelID)
 	if err != nil {
 		return err
@@ -67,6 +150,9 @@ func DoOutputBlock(config *Profile, channelID, outputBlock string) error {
 
 // DoOutputChannelCreateTx generate a config TX and writes it to a file.
 func DoOutputChannelCreateTx(conf, baseProfile *Profile, channelID, outputChannelCreateTx string) error {
+	if err := ValidateChannelID(channelID); err != nil {
+		return err
+	}
 	logger.Info("Generating new channel configtx")
 
 	var configtx *common.Envelope
@@ -90,18 +176,90 @@ func DoOutputChannelCreateTx(conf, baseProfile *Profile, channelID, outputChanne
 
 // DoInspectBlock inspects a block from a file.
 func DoInspectBlock(inspectBlock string) error {
+	return InspectBlockTo(os.Stdout, inspectBlock)
+}
+
+// DoInspectChannelBlock inspects a block from a file, after checking that it belongs to
+// the given channel. An empty channel ID skips the check.
+func DoInspectChannelBlock(inspectBlock, channelID string) error {
+	return inspectChannelBlockTo(os.Stdout, inspectBlock, channelID)
+}
+
+// InspectBlockTo writes the decoded contents of a block from a file to w, as JSON.
+// The JSON is canonical: the object keys are sorted, so identical blocks produce
+// byte-identical output that can be diffed.
+func InspectBlockTo(w io.Writer, blockPath string) error {
+	return inspectChannelBlockTo(w, blockPath, "")
+}
+
+func inspectChannelBlockTo(w io.Writer, blockPath, channelID string) error {
 	logger.Info("Inspecting block")
-	block, err := protoutil.ReadBlockFromFile(inspectBlock)
+	block, err := protoutil.ReadBlockFromFile(blockPath)
 	if err != nil {
 		return err
 	}
-	err = protolator.DeepMarshalJSON(os.Stdout, block)
+	if err = checkBlockChannelID(block, channelID); err != nil {
+		return err
+	}
+	// The block is encoded from a tree of maps, whose keys encoding/json sorts.
+	err = protolator.DeepMarshalJSON(w, block)
 	if err != nil {
 		return fmt.Errorf("malformed block contents: %s", err)
 	}
 	return nil
 }
 
+// InspectBlock reads a block from a file and returns its decoded contents as a map.
+// The map holds the same structure DoInspectBlock prints, so callers can assert on
+// config contents without scraping the output.
+func InspectBlock(path string) (map[string]any, error) {
+	return InspectChannelBlock(path, "")
+}
+
+// InspectChannelBlock is like InspectBlock, but fails if the block does not belong to
+// the given channel. An empty channel ID skips the check.
+func InspectChannelBlock(path, channelID string) (map[string]any, error) {
+	block, err := protoutil.ReadBlockFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkBlockChannelID(block, channelID); err != nil {
+		return nil, err
+	}
+	return decodeBlock(block)
+}
+
+// checkBlockChannelID fails if the channel header of the block names a channel other
+// than the expected one. An empty expected channel ID matches any block.
+func checkBlockChannelID(block *cb.Block, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	channelID, err := protoutil.GetChannelIDFromBlock(block)
+	if err != nil {
+		return errors.Wrap(err, "could not extract the channel ID from the block")
+	}
+	if channelID != expected {
+		return errors.Errorf("the block belongs to channel '%s', not to channel '%s'", channelID, expected)
+	}
+	return nil
+}
+
+// decodeBlock returns the decoded contents of a block as a map.
+func decodeBlock(block *cb.Block) (map[string]any, error) {
+	var buf bytes.Buffer
+	err := protolator.DeepMarshalJSON(&buf, block)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed block contents")
+	}
+	var decoded map[string]any
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode block contents")
+	}
+	return decoded, nil
+}
+
 // DoInspectChannelCreateTx inspects a config TX from a file.
 func DoInspectChannelCreateTx(inspectChannelCreateTx string) error {
 	logger.Info("Inspecting transaction")
@@ -124,6 +282,43 @@ func DoInspectChannelCreateTx(inspectChannelCreateTx string) error {
 	return nil
 }
 
+// SignChannelCreateTx reads a channel create transaction from a file and appends the signer's
+// signature over its config update. The returned envelope is re-signed by the signer.
+func SignChannelCreateTx(txPath string, signer identity.SignerSerializer) (*cb.Envelope, error) {
+	data, err := os.ReadFile(txPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read channel create tx")
+	}
+	env, err := protoutil.UnmarshalEnvelope(data)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := protoutil.ChannelHeader(env)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG_UPDATE) {
+		return nil, errors.Errorf("expected a config update transaction, but got header type %d", chdr.Type)
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	configSig, err := newConfigSignature(signer, configUpdateEnv.ConfigUpdate)
+	if err != nil {
+		return nil, err
+	}
+	configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, configSig)
+
+	return protoutil.CreateSignedEnvelope(
+		cb.HeaderType_CONFIG_UPDATE, chdr.ChannelId, signer, configUpdateEnv, msgVersion, epoch)
+}
+
 // DoPrintOrg prints organization info.
 func DoPrintOrg(t *TopLevel, printOrg string) error { //nolint:gocognit // cognitive complexity 20.
 	for _, org := range t.Organizations {
@@ -7,17 +7,28 @@ SPDX-License-Identifier: Apache-2.0
 package configtxgen
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric-lib-go/bccsp/factory"
+	cb "github.com/hyperledger/fabric-protos-go-apiv2/common"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/hyperledger/fabric-x-common/api/types"
+	"github.com/hyperledger/fabric-x-common/common/capabilities"
 	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/common/configtx"
+	"github.com/hyperledger/fabric-x-common/common/util"
 	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+	"github.com/hyperledger/fabric-x-common/msp"
 	"github.com/hyperledger/fabric-x-common/protoutil"
 )
 
@@ -37,6 +48,54 @@ func TestInspectBlock(t *testing.T) {
 	require.NoError(t, DoInspectBlock(blockDest), "Good block inspection request")
 }
 
+func TestInspectChannelBlock(t *testing.T) {
+	t.Parallel()
+	blockDest := filepath.Join(t.TempDir(), "block")
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	require.NoError(t, DoOutputBlock(config, "foo", blockDest))
+
+	require.NoError(t, DoInspectChannelBlock(blockDest, "foo"))
+	err := DoInspectChannelBlock(blockDest, "bar")
+	require.EqualError(t, err, "the block belongs to channel 'foo', not to channel 'bar'")
+
+	decoded, err := InspectChannelBlock(blockDest, "foo")
+	require.NoError(t, err)
+	require.NotEmpty(t, decoded)
+	_, err = InspectChannelBlock(blockDest, "bar")
+	require.EqualError(t, err, "the block belongs to channel 'foo', not to channel 'bar'")
+}
+
+func TestInspectBlockTo(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	blockDest := filepath.Join(testDir, "block")
+	require.NoError(t, DoOutputBlock(config, "foo", blockDest))
+
+	var out bytes.Buffer
+	require.NoError(t, InspectBlockTo(&out, blockDest))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	channelGroup := lookup(t, decoded, "data", "data", 0, "payload", "data", "config", "channel_group")
+	groups, ok := lookup(t, channelGroup, "groups").(map[string]any)
+	require.True(t, ok)
+	require.ElementsMatch(t,
+		[]string{channelconfig.ApplicationGroupKey, channelconfig.OrdererGroupKey}, slices.Collect(maps.Keys(groups)))
+
+	// Inspecting the same block twice, or an identical copy of it, yields the same bytes.
+	blockCopy := filepath.Join(testDir, "block-copy")
+	blockBytes, err := os.ReadFile(blockDest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(blockCopy, blockBytes, 0o600))
+	for _, path := range []string{blockDest, blockCopy} {
+		var again bytes.Buffer
+		require.NoError(t, InspectBlockTo(&again, path))
+		require.Equal(t, out.String(), again.String())
+	}
+
+	require.ErrorContains(t, InspectBlockTo(&out, filepath.Join(testDir, "missing")), "could not read block")
+}
+
 func TestInspectBlockErr(t *testing.T) {
 	t.Parallel()
 	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
@@ -159,7 +218,67 @@ func TestBftOrdererTypeWithoutCapabilitiesShouldNotRaiseAnError(t *testing.T) {
 	config := createBftOrdererConfig()
 
 	// ### Act & Assert
-	require.NoError(t, DoOutputBlock(config, "testChannelId", blockDest))
+	require.NoError(t, DoOutputBlock(config, "testchannelid", blockDest))
+}
+
+func TestDeriveCapabilities(t *testing.T) {
+	t.Parallel()
+	channelCapabilities := func(t *testing.T, config *Profile) map[string]*cb.Capability {
+		t.Helper()
+		block, err := GetOutputBlock(config, "foo")
+		require.NoError(t, err)
+		bundle, err := channelconfig.NewBundleFromBlock(block, factory.GetDefault())
+		require.NoError(t, err)
+		value := bundle.ConfigtxValidator().ConfigProto().ChannelGroup.Values[channelconfig.CapabilitiesKey]
+		if value == nil {
+			return nil
+		}
+		caps := &cb.Capabilities{}
+		require.NoError(t, proto.Unmarshal(value.Value, caps))
+		return caps.Capabilities
+	}
+
+	t.Run("BFT", func(t *testing.T) {
+		t.Parallel()
+		config := createBftOrdererConfig()
+		require.NotContains(t, channelCapabilities(t, config), capabilities.ChannelV3_0)
+
+		DeriveCapabilities(config)
+		require.Contains(t, channelCapabilities(t, config), capabilities.ChannelV3_0)
+	})
+
+	t.Run("not BFT", func(t *testing.T) {
+		t.Parallel()
+		config := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		DeriveCapabilities(config)
+		require.NotContains(t, channelCapabilities(t, config), capabilities.ChannelV3_0)
+	})
+}
+
+func TestOrdererOnlyGenesisBlock(t *testing.T) {
+	t.Parallel()
+	config := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+	config.Application = nil
+	config.Consortiums = nil
+
+	_, err := GetOutputBlock(config, "foo")
+	require.EqualError(t, err, "refusing to generate application channel block which is missing application section")
+
+	config.OrdererOnly = true
+	block, err := GetOutputBlock(config, "foo")
+	require.NoError(t, err)
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromEnvelope(env, factory.GetDefault())
+	require.NoError(t, err)
+	_, ok := bundle.OrdererConfig()
+	require.True(t, ok)
+	_, ok = bundle.ApplicationConfig()
+	require.False(t, ok)
+
+	config.Application = &Application{}
+	_, err = GetOutputBlock(config, "foo")
+	require.EqualError(t, err, "refusing to generate orderer-only channel block which has an application section")
 }
 
 func TestFabricXGenesisBlock(t *testing.T) {
@@ -227,3 +346,174 @@ func TestFabricXGenesisBlock(t *testing.T) {
 		})
 	}
 }
+
+func TestArmaInlineMetadata(t *testing.T) {
+	t.Parallel()
+	armaPath := filepath.Join(configtest.GetDevConfigDir(), "arma_shared_config.pbbin")
+	armaBytes, err := os.ReadFile(armaPath)
+	require.NoError(t, err)
+
+	for _, path := range []string{"", filepath.Join(t.TempDir(), "missing.pbbin")} {
+		config := Load(SampleFabricX, configtest.GetDevConfigDir())
+		config.Orderer.Arma.Path = path
+		config.Orderer.Arma.Bytes = armaBytes
+		block, err := GetOutputBlock(config, "foo")
+		require.NoError(t, err)
+
+		bundle, err := channelconfig.NewBundleFromBlock(block, factory.GetDefault())
+		require.NoError(t, err)
+		oc, ok := bundle.OrdererConfig()
+		require.True(t, ok)
+		require.Equal(t, armaBytes, oc.ConsensusMetadata())
+	}
+}
+
+func TestBlockFromChannelGroup(t *testing.T) {
+	t.Parallel()
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	group, err := NewChannelGroup(config)
+	require.NoError(t, err)
+
+	block, err := BlockFromChannelGroup("foo", group)
+	require.NoError(t, err)
+	require.Zero(t, block.Header.Number)
+
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromEnvelope(envelope, factory.GetDefault())
+	require.NoError(t, err)
+	require.Equal(t, "foo", bundle.ConfigtxValidator().ChannelID())
+
+	_, ok := bundle.ApplicationConfig()
+	require.True(t, ok)
+
+	_, err = BlockFromChannelGroup("", group)
+	require.EqualError(t, err, "channel ID must not be empty")
+	_, err = BlockFromChannelGroup("foo", nil)
+	require.EqualError(t, err, "channel group must not be nil")
+}
+
+func TestInspectBlockMap(t *testing.T) {
+	t.Parallel()
+	blockDest := filepath.Join(t.TempDir(), "block")
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	require.NoError(t, DoOutputBlock(config, "foo", blockDest))
+
+	decoded, err := InspectBlock(blockDest)
+	require.NoError(t, err)
+
+	payload := lookup(t, decoded, "data", "data", 0, "payload")
+	require.Equal(t, "foo", lookup(t, payload, "header", "channel_header", "channel_id"))
+	channelGroup := lookup(t, payload, "data", "config", "channel_group")
+	require.Equal(t, "solo", lookup(t, channelGroup, "groups", "Orderer", "values", "ConsensusType", "value", "type"))
+
+	_, err = InspectBlock(filepath.Join(t.TempDir(), "missing"))
+	require.ErrorContains(t, err, "could not read block")
+}
+
+// lookup walks a decoded JSON tree along the given map keys and slice indexes.
+func lookup(t *testing.T, tree any, path ...any) any {
+	t.Helper()
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			m, ok := tree.(map[string]any)
+			require.Truef(t, ok, "expected a map at %v", key)
+			tree = m[key]
+		case int:
+			s, ok := tree.([]any)
+			require.Truef(t, ok, "expected a slice at %d", key)
+			require.Greater(t, len(s), key)
+			tree = s[key]
+		}
+	}
+	return tree
+}
+
+func TestSignChannelCreateTx(t *testing.T) {
+	t.Parallel()
+	configTxDest := filepath.Join(t.TempDir(), "configtx")
+	config := Load(SampleSingleMSPChannelProfile, configtest.GetDevConfigDir())
+	require.NoError(t, DoOutputChannelCreateTx(config, nil, "foo", configTxDest))
+
+	localMsp, err := msp.LoadLocalMspDir(msp.DirLoadParameters{MspDir: configtest.GetDevMspDir()})
+	require.NoError(t, err)
+	signer, err := localMsp.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	creator, err := signer.Serialize()
+	require.NoError(t, err)
+
+	for expectedSigs := 1; expectedSigs <= 2; expectedSigs++ {
+		env, err := SignChannelCreateTx(configTxDest, signer)
+		require.NoError(t, err)
+
+		chdr, err := protoutil.ChannelHeader(env)
+		require.NoError(t, err)
+		require.Equal(t, "foo", chdr.ChannelId)
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		require.NoError(t, err)
+		configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+		require.NoError(t, err)
+		require.Len(t, configUpdateEnv.Signatures, expectedSigs)
+
+		sig := configUpdateEnv.Signatures[expectedSigs-1]
+		sigHeader, err := protoutil.UnmarshalSignatureHeader(sig.SignatureHeader)
+		require.NoError(t, err)
+		require.Equal(t, creator, sigHeader.Creator)
+		signedBytes := util.ConcatenateBytes(sig.SignatureHeader, configUpdateEnv.ConfigUpdate)
+		require.NoError(t, signer.Verify(signedBytes, sig.Signature))
+
+		require.NoError(t, os.WriteFile(configTxDest, protoutil.MarshalOrPanic(env), 0o600))
+	}
+}
+
+func TestSignChannelCreateTxNotAConfigUpdate(t *testing.T) {
+	t.Parallel()
+	blockDest := filepath.Join(t.TempDir(), "block")
+	config := Load(SampleAppChannelSmartBftProfile, configtest.GetDevConfigDir())
+	block, err := GetOutputBlock(config, "foo")
+	require.NoError(t, err)
+	env := protoutil.ExtractEnvelopeOrPanic(block, 0)
+	require.NoError(t, os.WriteFile(blockDest, protoutil.MarshalOrPanic(env), 0o600))
+
+	_, err = SignChannelCreateTx(blockDest, nil)
+	require.EqualError(t, err, "expected a config update transaction, but got header type 1")
+}
+
+func TestGenesisBlockHash(t *testing.T) {
+	t.Parallel()
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	config := Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	block, hash, err := GenesisBlockHash(config, "foo", timestamp)
+	require.NoError(t, err)
+	require.Len(t, hash, 32)
+	require.Equal(t, protoutil.BlockHeaderHash(block.Header), hash)
+	channelID, err := protoutil.GetChannelIDFromBlock(block)
+	require.NoError(t, err)
+	require.Equal(t, "foo", channelID)
+
+	// The written block has the same hash.
+	blockPath := filepath.Join(t.TempDir(), "genesis.block")
+	require.NoError(t, WriteOutputBlock(block, blockPath))
+	written, err := protoutil.ReadBlockFromFile(blockPath)
+	require.NoError(t, err)
+	require.Equal(t, hash, protoutil.BlockHeaderHash(written.Header))
+
+	for range 3 {
+		_, again, err := GenesisBlockHash(Load(SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir()),
+			"foo", timestamp)
+		require.NoError(t, err)
+		require.Equal(t, hash, again)
+	}
+
+	_, other, err := GenesisBlockHash(config, "foo", timestamp.Add(time.Hour))
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+	_, other, err = GenesisBlockHash(config, "bar", timestamp)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, other)
+
+	config.Orderer = nil
+	_, _, err = GenesisBlockHash(config, "foo", timestamp)
+	require.EqualError(t, err, "refusing to generate block which is missing orderer section")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/cockroachdb/errors"
+)
+
+// TransitionOption relaxes the checks of ValidateProfileTransition.
+type TransitionOption func(*transitionOptions)
+
+type transitionOptions struct {
+	allowOrgRemoval bool
+}
+
+// AllowOrgRemoval permits the new profile to drop organizations of the old profile.
+func AllowOrgRemoval() TransitionOption {
+	return func(o *transitionOptions) {
+		o.allowOrgRemoval = true
+	}
+}
+
+// ValidateProfileTransition checks that a channel configured with oldProfile may be updated to newProfile.
+// The consensus type must not change, capabilities may be enabled but not disabled, and organizations
+// may not be removed unless AllowOrgRemoval is given.
+func ValidateProfileTransition(oldProfile, newProfile *Profile, opts ...TransitionOption) error {
+	if oldProfile == nil || newProfile == nil {
+		return errors.New("profiles must not be nil")
+	}
+	var o transitionOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	err := validateCapabilityTransition("channel", oldProfile.Capabilities, newProfile.Capabilities)
+	if err != nil {
+		return err
+	}
+	if err = validateOrdererTransition(oldProfile.Orderer, newProfile.Orderer, o); err != nil {
+		return err
+	}
+	return validateApplicationTransition(oldProfile.Application, newProfile.Application, o)
+}
+
+func validateOrdererTransition(oldOrderer, newOrderer *Orderer, o transitionOptions) error {
+	if (oldOrderer == nil) != (newOrderer == nil) {
+		return errors.New("the orderer section cannot be added or removed")
+	}
+	if oldOrderer == nil {
+		return nil
+	}
+	if oldOrderer.OrdererType != newOrderer.OrdererType {
+		return errors.Newf("the consensus type cannot change from %s to %s",
+			oldOrderer.OrdererType, newOrderer.OrdererType)
+	}
+	err := validateCapabilityTransition("orderer", oldOrderer.Capabilities, newOrderer.Capabilities)
+	if err != nil {
+		return err
+	}
+	return validateOrgTransition("orderer", oldOrderer.Organizations, newOrderer.Organizations, o)
+}
+
+func validateApplicationTransition(oldApp, newApp *Application, o transitionOptions) error {
+	if (oldApp == nil) != (newApp == nil) {
+		return errors.New("the application section cannot be added or removed")
+	}
+	if oldApp == nil {
+		return nil
+	}
+	err := validateCapabilityTransition("application", oldApp.Capabilities, newApp.Capabilities)
+	if err != nil {
+		return err
+	}
+	return validateOrgTransition("application", oldApp.Organizations, newApp.Organizations, o)
+}
+
+// validateCapabilityTransition checks that every capability enabled in the old section remains enabled.
+func validateCapabilityTransition(section string, oldCapabilities, newCapabilities map[string]bool) error {
+	for _, capability := range slices.Sorted(maps.Keys(oldCapabilities)) {
+		if oldCapabilities[capability] && !newCapabilities[capability] {
+			return errors.Newf("the %s capability %s cannot be disabled", section, capability)
+		}
+	}
+	return nil
+}
+
+func validateOrgTransition(section string, oldOrgs, newOrgs []*Organization, o transitionOptions) error {
+	if o.allowOrgRemoval {
+		return nil
+	}
+	for _, oldOrg := range oldOrgs {
+		if !slices.ContainsFunc(newOrgs, func(org *Organization) bool { return org.Name == oldOrg.Name }) {
+			return errors.Newf("the %s organization %s cannot be removed", section, oldOrg.Name)
+		}
+	}
+	return nil
+}
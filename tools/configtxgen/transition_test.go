@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/core/config/configtest"
+)
+
+func TestValidateProfileTransition(t *testing.T) {
+	t.Parallel()
+	oldProfile := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+
+	t.Run("batch timeout change", func(t *testing.T) {
+		t.Parallel()
+		newProfile := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		newProfile.Orderer.BatchTimeout += time.Second
+		require.NoError(t, ValidateProfileTransition(oldProfile, newProfile))
+	})
+
+	t.Run("consensus type change", func(t *testing.T) {
+		t.Parallel()
+		newProfile := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		newProfile.Orderer.OrdererType = "etcdraft"
+		err := ValidateProfileTransition(oldProfile, newProfile)
+		require.EqualError(t, err, "the consensus type cannot change from solo to etcdraft")
+	})
+
+	t.Run("capability disabled", func(t *testing.T) {
+		t.Parallel()
+		enabledProfile := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		enabledProfile.Capabilities = map[string]bool{"V3_0": true}
+		require.NoError(t, ValidateProfileTransition(oldProfile, enabledProfile))
+		err := ValidateProfileTransition(enabledProfile, oldProfile)
+		require.EqualError(t, err, "the channel capability V3_0 cannot be disabled")
+	})
+
+	t.Run("organization removed", func(t *testing.T) {
+		t.Parallel()
+		newProfile := Load(SampleDevModeSoloProfile, configtest.GetDevConfigDir())
+		newProfile.Orderer.Organizations = nil
+		err := ValidateProfileTransition(oldProfile, newProfile)
+		require.EqualError(t, err, "the orderer organization SampleOrg cannot be removed")
+		require.NoError(t, ValidateProfileTransition(oldProfile, newProfile, AllowOrgRemoval()))
+	})
+}
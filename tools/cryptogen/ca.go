@@ -11,12 +11,14 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
 	"math/big"
 	"net"
+	"net/url"
 	"os"
 	"time"
 
@@ -34,6 +36,21 @@ type caParams struct {
 	StreetAddress      string
 	PostalCode         string
 	KeyAlgorithm       string
+	ECDSACurve         string
+	// EmitDER also writes the certificates generated by this CA in DER.
+	EmitDER bool
+	// MaxPathLen limits the number of intermediate CAs that may follow this CA in a chain. Nil means no limit.
+	MaxPathLen *int
+	// AlternateNames are the SANs of the CA certificate.
+	AlternateNames []string
+	// Email is the email SAN of the CA certificate, if set.
+	Email string
+	// Expiry and NotBefore set the validity period of the CA certificate. Zero values select the defaults.
+	Expiry    time.Duration
+	NotBefore *time.Time
+	// SerialNumbers generates the serial numbers of the certificates issued by the CA.
+	// When nil, random serial numbers are issued.
+	SerialNumbers serialNumberGenerator
 
 	// These fields are filled by the buildCA() method.
 	Signer   crypto.Signer
@@ -44,9 +61,14 @@ type caParams struct {
 type signCertParams struct {
 	OrgUnits       []string
 	AlternateNames []string
+	Email          string
+	EmailSANs      []string
+	URISANs        []string
 	KeyUsage       x509.KeyUsage
 	ExtKeyUsage    []x509.ExtKeyUsage
 	PublicKey      crypto.PublicKey
+	Expiry         time.Duration
+	NotBefore      *time.Time
 }
 
 type certParams struct {
@@ -54,12 +76,21 @@ type certParams struct {
 	Parent     *x509.Certificate
 	PublicKey  crypto.PublicKey
 	PrivateKey any
+	EmitDER    bool
 }
 
-// caFromSpec creates a CA from a node spec, generates, and saves the signing key pair in baseDir/name.
-func caFromSpec(baseDir, orgName, namePrefix string, s *NodeSpec) (*caParams, error) {
+// caFromSpec creates a CA from an organization's CA spec, generates, and saves the signing key pair in baseDir/name.
+func caFromSpec(baseDir, namePrefix string, org *OrgSpec) (*caParams, error) {
+	s := &org.CA
+	if s.MaxPathLen != nil && *s.MaxPathLen < 0 {
+		return nil, errors.Newf("invalid MaxPathLen %d of CA %s: must not be negative", *s.MaxPathLen, s.CommonName)
+	}
+	serialNumbers, err := newSerialNumberGenerator(s.SerialNumberStrategy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid CA %s", s.CommonName)
+	}
 	newCA := &caParams{
-		Organization:       orgName,
+		Organization:       org.Domain,
 		Name:               namePrefix + s.CommonName,
 		Country:            s.Country,
 		Province:           s.Province,
@@ -68,8 +99,16 @@ func caFromSpec(baseDir, orgName, namePrefix string, s *NodeSpec) (*caParams, er
 		StreetAddress:      s.StreetAddress,
 		PostalCode:         s.PostalCode,
 		KeyAlgorithm:       s.PublicKeyAlgorithm,
+		ECDSACurve:         s.ECDSACurve,
+		EmitDER:            org.EmitDER,
+		MaxPathLen:         s.MaxPathLen,
+		AlternateNames:     s.AlternateNames,
+		Email:              s.Email,
+		Expiry:             s.Expiry,
+		NotBefore:          s.NotBefore,
+		SerialNumbers:      serialNumbers,
 	}
-	err := buildCA(baseDir, newCA)
+	err = buildCA(baseDir, newCA)
 	return newCA, err
 }
 
@@ -80,22 +119,32 @@ func buildCA(baseDir string, ca *caParams) error {
 		return errors.Wrapf(err, "cannot create directory %s", baseDir)
 	}
 
-	priv, err := generatePrivateKey(baseDir, ca.KeyAlgorithm)
+	priv, err := generatePrivateKey(baseDir, ca.KeyAlgorithm, ca.ECDSACurve)
 	if err != nil {
 		return err
 	}
 	ca.Signer = newSignerFromPrivateKey(priv)
 
-	template := x509Template()
+	template := x509Template(ca.Expiry, ca.NotBefore)
+	template.SerialNumber, err = ca.nextSerialNumber()
+	if err != nil {
+		return err
+	}
 	// this is a CA
 	template.IsCA = true
 	template.KeyUsage |= x509.KeyUsageDigitalSignature |
 		x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign |
 		x509.KeyUsageCRLSign
+	if ca.MaxPathLen != nil {
+		template.MaxPathLen = *ca.MaxPathLen
+		template.MaxPathLenZero = *ca.MaxPathLen == 0
+	}
 	template.ExtKeyUsage = []x509.ExtKeyUsage{
 		x509.ExtKeyUsageClientAuth,
 		x509.ExtKeyUsageServerAuth,
 	}
+	addAlternateNames(&template, ca.AlternateNames)
+	addEmail(&template, ca.Email)
 
 	// set the organization for the subject
 	subject := subjectTemplateAdditional(ca)
@@ -113,6 +162,7 @@ func buildCA(baseDir string, ca *caParams) error {
 		Parent:     &template,
 		PublicKey:  getPublicKey(priv),
 		PrivateKey: priv,
+		EmitDER:    ca.EmitDER,
 	})
 	return err
 }
@@ -126,6 +176,10 @@ func loadCA(caDir string, spec *OrgSpec, name string) (*caParams, error) {
 	if err != nil {
 		return nil, err
 	}
+	serialNumbers, err := newSerialNumberGenerator(spec.CA.SerialNumberStrategy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid CA %s", name)
+	}
 	return &caParams{
 		Name:               name,
 		Signer:             newSignerFromPrivateKey(privateKey),
@@ -136,12 +190,19 @@ func loadCA(caDir string, spec *OrgSpec, name string) (*caParams, error) {
 		OrganizationalUnit: spec.CA.OrganizationalUnit,
 		StreetAddress:      spec.CA.StreetAddress,
 		PostalCode:         spec.CA.PostalCode,
+		EmitDER:            spec.EmitDER,
+		SerialNumbers:      serialNumbers,
 	}, nil
 }
 
 // signCertificate creates a signed certificate based on a built-in template and saves it in baseDir/name.
 func (ca *caParams) signCertificate(baseDir, name string, p signCertParams) (*x509.Certificate, error) {
-	template := x509Template()
+	serialNumber, err := ca.nextSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := x509Template(p.Expiry, p.NotBefore)
+	template.SerialNumber = serialNumber
 	template.KeyUsage = p.KeyUsage
 	template.ExtKeyUsage = p.ExtKeyUsage
 
@@ -151,7 +212,44 @@ func (ca *caParams) signCertificate(baseDir, name string, p signCertParams) (*x5
 	subject.OrganizationalUnit = append(subject.OrganizationalUnit, p.OrgUnits...)
 
 	template.Subject = subject
-	for _, san := range p.AlternateNames {
+	addAlternateNames(&template, p.AlternateNames)
+	addEmail(&template, p.Email)
+	template.EmailAddresses = append(template.EmailAddresses, p.EmailSANs...)
+	err = addURIs(&template, p.URISANs)
+	if err != nil {
+		return nil, err
+	}
+
+	return genCertificate(baseDir, name, certParams{
+		Template:   &template,
+		Parent:     ca.SignCert,
+		PublicKey:  p.PublicKey,
+		PrivateKey: ca.Signer,
+		EmitDER:    ca.EmitDER,
+	})
+}
+
+// generateOCSPResponder generates an OCSP responder key pair in baseDir, with a certificate signed by the CA
+// for the OCSP signing extended key usage.
+func (ca *caParams) generateOCSPResponder(baseDir string) (*x509.Certificate, error) {
+	err := os.MkdirAll(baseDir, 0o750)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot create directory %s", baseDir)
+	}
+	priv, err := generatePrivateKey(baseDir, getPublicKeyAlg(ca.KeyAlgorithm), ca.ECDSACurve)
+	if err != nil {
+		return nil, err
+	}
+	return ca.signCertificate(baseDir, OCSPPrefix+"."+ca.Name, signCertParams{
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+		PublicKey:   getPublicKey(priv),
+	})
+}
+
+// addAlternateNames adds the SANs to the template, as IP addresses or DNS names.
+func addAlternateNames(template *x509.Certificate, names []string) {
+	for _, san := range names {
 		// try to parse as an IP address first
 		ip := net.ParseIP(san)
 		if ip != nil {
@@ -160,13 +258,37 @@ func (ca *caParams) signCertificate(baseDir, name string, p signCertParams) (*x5
 			template.DNSNames = append(template.DNSNames, san)
 		}
 	}
+}
 
-	return genCertificate(baseDir, name, certParams{
-		Template:   &template,
-		Parent:     ca.SignCert,
-		PublicKey:  p.PublicKey,
-		PrivateKey: ca.Signer,
-	})
+// addEmail adds the email address, if any, to the template as an email SAN.
+func addEmail(template *x509.Certificate, email string) {
+	if email != "" {
+		template.EmailAddresses = append(template.EmailAddresses, email)
+	}
+}
+
+// addURIs adds the URIs to the template as URI SANs.
+func addURIs(template *x509.Certificate, uris []string) error {
+	for _, rawURI := range uris {
+		uri, err := parseURISAN(rawURI)
+		if err != nil {
+			return err
+		}
+		template.URIs = append(template.URIs, uri)
+	}
+	return nil
+}
+
+// parseURISAN parses a URI SAN, which must be absolute.
+func parseURISAN(rawURI string) (*url.URL, error) {
+	uri, err := url.Parse(rawURI)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid URI SAN %q", rawURI)
+	}
+	if uri.Scheme == "" {
+		return nil, errors.Newf("invalid URI SAN %q: missing scheme", rawURI)
+	}
+	return uri, nil
 }
 
 // computeSKI compute Subject Key Identifier using RFC 7093, Section 2, Method 4.
@@ -185,6 +307,9 @@ func computeSKI(privKey crypto.PrivateKey) ([]byte, error) {
 	case ed25519.PrivateKey:
 		//nolint:errcheck,revive,forcetypeassert // implementation always returns this type.
 		raw = kk.Public().(ed25519.PublicKey)
+	case *rsa.PrivateKey:
+		// The subject public key of RSA is the PKCS#1 encoding of the modulus and the exponent.
+		raw = x509.MarshalPKCS1PublicKey(&kk.PublicKey)
 	}
 
 	// Hash it
@@ -225,22 +350,35 @@ func subjectTemplateAdditional(ca *caParams) pkix.Name {
 	return name
 }
 
-// x509Template default template for X509 certificates.
-func x509Template() x509.Certificate {
-	// generate a serial number
-	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
-	serialNumber, _ := rand.Int(rand.Reader, serialNumberLimit)
+// defaultExpiry is the validity period of the certificates, when not configured: around 10 years.
+const defaultExpiry = 3650 * 24 * time.Hour
 
-	// set expiry to around 10 years
-	expiry := 3650 * 24 * time.Hour
-	// round minute and backdate 5 minutes
-	notBefore := time.Now().Round(time.Minute).Add(-5 * time.Minute).UTC()
+// nextSerialNumber returns the serial number of the next certificate issued by the CA.
+func (ca *caParams) nextSerialNumber() (*big.Int, error) {
+	if ca.SerialNumbers == nil {
+		return randomSerialNumber()
+	}
+	return ca.SerialNumbers.nextSerialNumber()
+}
+
+// x509Template default template for X509 certificates, without a serial number.
+// The certificate is valid for expiry from notBefore. Zero values select the defaults.
+func x509Template(expiry time.Duration, notBefore *time.Time) x509.Certificate {
+	if expiry == 0 {
+		expiry = defaultExpiry
+	}
+	var start time.Time
+	if notBefore != nil {
+		start = notBefore.UTC()
+	} else {
+		// round minute and backdate 5 minutes
+		start = time.Now().Round(time.Minute).Add(-5 * time.Minute).UTC()
+	}
 
 	// basic template to use
 	return x509.Certificate{
-		SerialNumber:          serialNumber,
-		NotBefore:             notBefore,
-		NotAfter:              notBefore.Add(expiry).UTC(),
+		NotBefore:             start,
+		NotAfter:              start.Add(expiry).UTC(),
 		BasicConstraintsValid: true,
 	}
 }
@@ -258,7 +396,11 @@ func genCertificate(baseDir, name string, p certParams) (*x509.Certificate, erro
 		return nil, errors.Wrap(err, "failed to parse certificate")
 	}
 
-	return x509Cert, writePEM(x509FilePath(baseDir, name), CertType, certBytes)
+	err = writePEM(x509FilePath(baseDir, name), CertType, certBytes)
+	if err != nil || !p.EmitDER {
+		return x509Cert, err
+	}
+	return x509Cert, writeDER(derFilePath(baseDir, name), certBytes)
 }
 
 // newSignerFromPrivateKey creates a signer from a private key.
@@ -272,6 +414,8 @@ func newSignerFromPrivateKey(priv crypto.PrivateKey) crypto.Signer {
 		return &ED25519Signer{
 			PrivateKey: kk,
 		}
+	case *rsa.PrivateKey:
+		return kk
 	default:
 		panic("unsupported key algorithm")
 	}
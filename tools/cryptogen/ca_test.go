@@ -8,14 +8,24 @@ package cryptogen
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
 	"net"
 	"os"
 	"path"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/msp"
 )
 
 const (
@@ -41,7 +51,7 @@ func TestLoadCertificateECDSA(t *testing.T) {
 	// generate private key
 	certDir := path.Join(testDir, "certs")
 	require.NoError(t, os.MkdirAll(certDir, 0o750))
-	privGeneric, err := generatePrivateKey(certDir, ECDSA)
+	privGeneric, err := generatePrivateKey(certDir, ECDSA, "")
 	require.NoError(t, err, "Failed to generate signed certificate")
 	priv, ok := privGeneric.(*ecdsa.PrivateKey)
 	require.True(t, ok)
@@ -126,6 +136,62 @@ func TestNewCA(t *testing.T) {
 	require.Equal(t, caTestPostalCode, rootCA.SignCert.Subject.PostalCode[0], "Failed to match postalCode")
 }
 
+func TestCAMaxPathLen(t *testing.T) {
+	t.Parallel()
+	zero := 0
+	org := &OrgSpec{Domain: "example.com", CA: NodeSpec{
+		CommonName: "ca.example.com", PublicKeyAlgorithm: ECDSA, MaxPathLen: &zero,
+	}}
+	rootCA, err := caFromSpec(t.TempDir(), "", org)
+	require.NoError(t, err)
+	require.True(t, rootCA.SignCert.MaxPathLenZero)
+	require.Zero(t, rootCA.SignCert.MaxPathLen)
+
+	// A sub-CA signed by a CA with a path length of 0 does not verify.
+	subCA, subCAKey := issueTestCert(t, "sub-ca", rootCA.SignCert, rootCA.Signer, true)
+	leaf, _ := issueTestCert(t, "leaf", subCA, subCAKey, false)
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCA.SignCert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(subCA)
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+	require.ErrorContains(t, err, "too many intermediates for path length constraint")
+
+	// Leaves signed directly by the CA still verify.
+	leaf, _ = issueTestCert(t, "leaf", rootCA.SignCert, rootCA.Signer, false)
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots})
+	require.NoError(t, err)
+
+	negative := -1
+	org.CA.MaxPathLen = &negative
+	_, err = caFromSpec(t.TempDir(), "", org)
+	require.EqualError(t, err, "invalid MaxPathLen -1 of CA ca.example.com: must not be negative")
+}
+
+// issueTestCert issues a certificate signed by the given parent, and returns it with its private key.
+func issueTestCert(
+	t *testing.T, name string, parent *x509.Certificate, parentKey any, isCA bool,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             parent.NotBefore,
+		NotAfter:              parent.NotAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &priv.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, priv
+}
+
 func TestGenerateSignCertificate(t *testing.T) {
 	t.Parallel()
 	testDir := t.TempDir()
@@ -133,7 +199,7 @@ func TestGenerateSignCertificate(t *testing.T) {
 	// generate private key
 	certDir := path.Join(testDir, "certs")
 	require.NoError(t, os.MkdirAll(certDir, 0o750))
-	privGeneric, err := generatePrivateKey(certDir, ECDSA)
+	privGeneric, err := generatePrivateKey(certDir, ECDSA, "")
 	require.NoError(t, err, "Failed to generate signed certificate")
 	priv, ok := privGeneric.(*ecdsa.PrivateKey)
 	require.True(t, ok)
@@ -227,3 +293,376 @@ func defaultCA(t *testing.T, name, caDir string) *caParams {
 	require.NoError(t, err, "Error generating CA")
 	return &rootCA
 }
+
+func TestGenerateSignCertificateP384(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+
+	certDir := path.Join(testDir, "certs")
+	require.NoError(t, os.MkdirAll(certDir, 0o750))
+	privGeneric, err := generatePrivateKey(certDir, ECDSA, P384)
+	require.NoError(t, err)
+	priv, ok := privGeneric.(*ecdsa.PrivateKey)
+	require.True(t, ok)
+
+	rootCA := &caParams{
+		Organization: caTestCAName,
+		Name:         caTestCAName,
+		KeyAlgorithm: ECDSA,
+		ECDSACurve:   P384,
+	}
+	require.NoError(t, buildCA(path.Join(testDir, "ca"), rootCA))
+	caPub, ok := rootCA.SignCert.PublicKey.(*ecdsa.PublicKey)
+	require.True(t, ok)
+	require.Equal(t, elliptic.P384(), caPub.Curve)
+
+	cert, err := rootCA.signCertificate(certDir, caTestName, signCertParams{
+		PublicKey: &priv.PublicKey,
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+	})
+	require.NoError(t, err)
+	certPub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	require.True(t, ok)
+	require.Equal(t, elliptic.P384(), certPub.Curve)
+	require.NoError(t, cert.CheckSignatureFrom(rootCA.SignCert))
+}
+
+func TestGenerateCertificateDER(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+
+	rootCA := &caParams{
+		Organization: caTestCAName,
+		Name:         caTestCAName,
+		KeyAlgorithm: ECDSA,
+		EmitDER:      true,
+	}
+	caDir := path.Join(testDir, "ca")
+	require.NoError(t, buildCA(caDir, rootCA))
+	requireSameCertificateDER(t, caDir, caTestCAName)
+
+	certDir := path.Join(testDir, "certs")
+	require.NoError(t, os.MkdirAll(certDir, 0o750))
+	priv, err := generatePrivateKey(certDir, ECDSA, "")
+	require.NoError(t, err)
+	_, err = rootCA.signCertificate(certDir, caTestName, signCertParams{
+		PublicKey: getPublicKey(priv),
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+	})
+	require.NoError(t, err)
+	requireSameCertificateDER(t, certDir, caTestName)
+
+	// DER is not emitted by default.
+	rootCA.EmitDER = false
+	_, err = rootCA.signCertificate(certDir, caTestName2, signCertParams{
+		PublicKey: getPublicKey(priv),
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+	})
+	require.NoError(t, err)
+	require.FileExists(t, x509FilePath(certDir, caTestName2))
+	require.NoFileExists(t, derFilePath(certDir, caTestName2))
+}
+
+// requireSameCertificateDER asserts the PEM and DER encodings of a certificate exist and decode to the same one.
+func requireSameCertificateDER(t *testing.T, dir, name string) {
+	t.Helper()
+	pemCert, err := loadCertificateFile(x509FilePath(dir, name))
+	require.NoError(t, err)
+	der, err := os.ReadFile(derFilePath(dir, name))
+	require.NoError(t, err)
+	derCert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	require.True(t, pemCert.Equal(derCert))
+}
+
+func TestGenerateEmitDER(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	conf := peerOrgConfig(false)
+	conf.PeerOrgs[0].EmitDER = true
+	conf.PeerOrgs[0].Users.Count = 1
+	require.NoError(t, Generate(testDir, conf))
+
+	userDir := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com", UsersDir, "User1@peer-org.com")
+	requireSameCertificateDER(t, filepath.Join(userDir, MSPDir, SignCertsDir), "User1@peer-org.com")
+	require.FileExists(t, filepath.Join(userDir, TLSDir, ClientPrefix+DERFileExt))
+
+	localMsp, err := msp.LoadLocalMspDir(msp.DirLoadParameters{MspDir: filepath.Join(userDir, MSPDir)})
+	require.NoError(t, err)
+	require.NotNil(t, localMsp)
+}
+
+func TestCAAlternateNames(t *testing.T) {
+	t.Parallel()
+	org := &OrgSpec{Domain: "example.com", CA: NodeSpec{
+		CommonName:         "ca.example.com",
+		PublicKeyAlgorithm: ECDSA,
+		AlternateNames:     []string{"ocsp.example.com", "10.0.0.1"},
+	}}
+	rootCA, err := caFromSpec(t.TempDir(), "", org)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ocsp.example.com"}, rootCA.SignCert.DNSNames)
+	require.Len(t, rootCA.SignCert.IPAddresses, 1)
+	require.Equal(t, "10.0.0.1", rootCA.SignCert.IPAddresses[0].String())
+
+	// Without alternate names, the CA certificate has no SANs.
+	org.CA.AlternateNames = nil
+	rootCA, err = caFromSpec(t.TempDir(), "", org)
+	require.NoError(t, err)
+	require.Empty(t, rootCA.SignCert.DNSNames)
+	require.Empty(t, rootCA.SignCert.IPAddresses)
+}
+
+func TestCASerialNumberStrategy(t *testing.T) {
+	t.Parallel()
+	org := &OrgSpec{Domain: "example.com", CA: NodeSpec{
+		CommonName:           "ca.example.com",
+		PublicKeyAlgorithm:   ECDSA,
+		SerialNumberStrategy: SerialNumberMonotonic,
+	}}
+	testDir := t.TempDir()
+	rootCA, err := caFromSpec(filepath.Join(testDir, "ca"), "", org)
+	require.NoError(t, err)
+
+	// The serial numbers of the signed certificates increase after the one of the CA certificate.
+	last := rootCA.SignCert.SerialNumber
+	for i := range 3 {
+		priv, err := generatePrivateKey(testDir, ECDSA, "")
+		require.NoError(t, err)
+		cert, err := rootCA.signCertificate(testDir, fmt.Sprintf("cert%d", i), signCertParams{
+			KeyUsage:  x509.KeyUsageDigitalSignature,
+			PublicKey: getPublicKey(priv),
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, cert.SerialNumber.Cmp(last), "serial number %d", i)
+		last = cert.SerialNumber
+	}
+
+	// The random serial numbers are unique.
+	random, err := newSerialNumberGenerator(SerialNumberRandom)
+	require.NoError(t, err)
+	seen := make(map[string]struct{})
+	for range 100 {
+		serialNumber, err := random.nextSerialNumber()
+		require.NoError(t, err)
+		require.NotContains(t, seen, serialNumber.String())
+		seen[serialNumber.String()] = struct{}{}
+	}
+
+	org.CA.SerialNumberStrategy = "sequential"
+	_, err = caFromSpec(t.TempDir(), "", org)
+	require.EqualError(t, err, "invalid CA ca.example.com: unsupported serial number strategy 'sequential'")
+}
+
+func TestSignCertificateSANs(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	rootCA := defaultCA(t, caTestCAName, filepath.Join(testDir, "ca"))
+	priv, err := generatePrivateKey(testDir, ECDSA, "")
+	require.NoError(t, err)
+
+	cert, err := rootCA.signCertificate(testDir, caTestName, signCertParams{
+		AlternateNames: []string{"*.org1.example.com", "peer0.org1.example.com", "10.0.0.1"},
+		EmailSANs:      []string{"peer0@org1.example.com"},
+		URISANs:        []string{"spiffe://org1.example.com/peer0"},
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		PublicKey:      getPublicKey(priv),
+	})
+	require.NoError(t, err)
+
+	// The SANs survive a round trip through the DER encoding.
+	parsed, err := x509.ParseCertificate(cert.Raw)
+	require.NoError(t, err)
+	require.Equal(t, []string{"*.org1.example.com", "peer0.org1.example.com"}, parsed.DNSNames)
+	require.Len(t, parsed.IPAddresses, 1)
+	require.Equal(t, "10.0.0.1", parsed.IPAddresses[0].String())
+	require.Equal(t, []string{"peer0@org1.example.com"}, parsed.EmailAddresses)
+	require.Len(t, parsed.URIs, 1)
+	require.Equal(t, "spiffe://org1.example.com/peer0", parsed.URIs[0].String())
+
+	_, err = rootCA.signCertificate(testDir, caTestName2, signCertParams{
+		URISANs:   []string{"org1.example.com/peer0"},
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+		PublicKey: getPublicKey(priv),
+	})
+	require.EqualError(t, err, `invalid URI SAN "org1.example.com/peer0": missing scheme`)
+}
+
+func TestGenerateRSA(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		keyAlg  string
+		keySize int
+	}{
+		{keyAlg: RSA, keySize: 2048},
+		{keyAlg: RSA2048, keySize: 2048},
+		{keyAlg: RSA4096, keySize: 4096},
+	} {
+		t.Run(tc.keyAlg, func(t *testing.T) {
+			t.Parallel()
+			testDir := t.TempDir()
+			rootCA := &caParams{
+				Organization: caTestCAName,
+				Name:         caTestCAName,
+				KeyAlgorithm: tc.keyAlg,
+			}
+			require.NoError(t, buildCA(filepath.Join(testDir, "ca"), rootCA))
+			caPub, ok := rootCA.SignCert.PublicKey.(*rsa.PublicKey)
+			require.True(t, ok)
+			require.Equal(t, tc.keySize, caPub.N.BitLen())
+			ski := sha256.Sum256(x509.MarshalPKCS1PublicKey(caPub))
+			require.Equal(t, ski[:], rootCA.SignCert.SubjectKeyId)
+
+			certDir := filepath.Join(testDir, "certs")
+			require.NoError(t, os.MkdirAll(certDir, 0o750))
+			priv, err := generatePrivateKey(certDir, tc.keyAlg, "")
+			require.NoError(t, err)
+			cert, err := rootCA.signCertificate(certDir, caTestName, signCertParams{
+				PublicKey: getPublicKey(priv),
+				KeyUsage:  x509.KeyUsageDigitalSignature,
+			})
+			require.NoError(t, err)
+			require.Equal(t, x509.RSA, cert.PublicKeyAlgorithm)
+			require.NoError(t, cert.CheckSignatureFrom(rootCA.SignCert))
+
+			loaded, err := loadPrivateKey(certDir)
+			require.NoError(t, err)
+			// The keys are compared by value, as their internal representations may differ in padding.
+			rsaPriv, ok := priv.(*rsa.PrivateKey)
+			require.True(t, ok)
+			require.True(t, rsaPriv.Equal(loaded))
+		})
+	}
+}
+
+func TestGenerateRSALocalMSP(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	signCA := &caParams{Organization: mspTestCAName, Name: mspTestCAName, KeyAlgorithm: RSA2048}
+	require.NoError(t, buildCA(filepath.Join(testDir, "ca"), signCA))
+	tlsCA := &caParams{Organization: mspTestCAName, Name: mspTestCAName, KeyAlgorithm: RSA2048}
+	require.NoError(t, buildCA(filepath.Join(testDir, "tlsca"), tlsCA))
+
+	// The MSP supports RSA CAs issuing ECDSA and ed25519 identities.
+	tree := newMspTree(filepath.Join(testDir, "node"))
+	require.NoError(t, tree.generateLocalMSP(nodeParameters{
+		Name:      mspTestName,
+		OU:        PeerOU,
+		KeyAlg:    ECDSA,
+		SignCa:    signCA,
+		TLSCa:     tlsCA,
+		EnableOUs: true,
+	}))
+
+	localMsp, err := msp.LoadLocalMspDir(msp.DirLoadParameters{MspDir: tree.MSP})
+	require.NoError(t, err)
+	signer, err := localMsp.GetDefaultSigningIdentity()
+	require.NoError(t, err)
+	require.NoError(t, localMsp.Validate(signer.GetPublicVersion()))
+	message := []byte("message")
+	sig, err := signer.Sign(message)
+	require.NoError(t, err)
+	require.NoError(t, signer.Verify(message, sig))
+
+	// But it cannot load RSA identities, so the config validation rejects them.
+	rsaTree := newMspTree(filepath.Join(testDir, "rsa-node"))
+	require.NoError(t, rsaTree.generateLocalMSP(nodeParameters{
+		Name:      mspTestName,
+		OU:        PeerOU,
+		KeyAlg:    RSA2048,
+		SignCa:    signCA,
+		TLSCa:     tlsCA,
+		EnableOUs: true,
+	}))
+	_, err = msp.LoadLocalMspDir(msp.DirLoadParameters{MspDir: rsaTree.MSP})
+	require.ErrorContains(t, err, "KeyMaterial not found in SigningIdentityInfo")
+
+	conf := peerOrgConfig(true)
+	conf.PeerOrgs[0].CA.PublicKeyAlgorithm = RSA2048
+	conf.PeerOrgs[0].Specs = []NodeSpec{{Hostname: "peer0", PublicKeyAlgorithm: RSA2048}}
+	err = Generate(filepath.Join(testDir, "crypto"), conf)
+	require.ErrorContains(t, err, "organization 'PeerOrg' has an RSA key algorithm 'rsa2048' for node 'peer0', "+
+		"but RSA keys are only supported for the CA")
+}
+
+func TestGenerateOCSPResponder(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	conf := peerOrgConfig(true)
+	conf.PeerOrgs[0].OCSPResponder = true
+	require.NoError(t, Generate(testDir, conf))
+
+	orgPath := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com")
+	for _, caDir := range []string{CaDir, TLSCaDir} {
+		ca, err := loadCertificate(filepath.Join(orgPath, caDir))
+		require.NoError(t, err)
+		responderDir := filepath.Join(orgPath, OCSPDir, caDir)
+		require.FileExists(t, filepath.Join(responderDir, PrivateKeyFile))
+		responder, err := loadCertificate(responderDir)
+		require.NoError(t, err)
+		require.Equal(t, OCSPPrefix+"."+ca.Subject.CommonName, responder.Subject.CommonName)
+		require.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}, responder.ExtKeyUsage)
+		require.False(t, responder.IsCA)
+
+		// As with RFC 6960 delegated responders, the responder certificate is issued directly by the CA.
+		require.NoError(t, responder.CheckSignatureFrom(ca))
+		roots := x509.NewCertPool()
+		roots.AddCert(ca)
+		_, err = responder.Verify(x509.VerifyOptions{
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		require.NoError(t, err)
+	}
+
+	// Without the flag, no OCSP responders are generated.
+	testDir = t.TempDir()
+	require.NoError(t, Generate(testDir, peerOrgConfig(true)))
+	require.NoDirExists(t, filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com", OCSPDir))
+}
+
+func TestGenerateExpiry(t *testing.T) {
+	t.Parallel()
+	conf, err := ParseConfig(`
+PeerOrgs:
+  - Name: Org1
+    Domain: org1.com
+    CA:
+      Hostname: ca
+      Expiry: 8760h
+      NotBefore: 2025-01-01T00:00:00Z
+    Template:
+      Count: 1
+    Specs:
+      - Hostname: short-lived
+        Expiry: 720h
+`)
+	require.NoError(t, err)
+	testDir := t.TempDir()
+	require.NoError(t, Generate(testDir, conf))
+
+	orgPath := filepath.Join(testDir, PeerOrganizationsDir, "org1.com")
+	for _, caDir := range []string{CaDir, TLSCaDir} {
+		caCert, err := loadCertificate(filepath.Join(orgPath, caDir))
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), caCert.NotBefore, caDir)
+		require.Equal(t, 8760*time.Hour, caCert.NotAfter.Sub(caCert.NotBefore), caDir)
+	}
+
+	for node, expiry := range map[string]time.Duration{
+		"short-lived.org1.com": 720 * time.Hour,
+		"peer0":                defaultExpiry,
+	} {
+		nodeDir := filepath.Join(orgPath, PeerNodesDir, node)
+		signCert, err := loadCertificate(filepath.Join(nodeDir, MSPDir, SignCertsDir))
+		require.NoError(t, err)
+		require.Equal(t, expiry, signCert.NotAfter.Sub(signCert.NotBefore), node)
+		tlsCert, err := loadCertificateFile(filepath.Join(nodeDir, TLSDir, ServerPrefix+".crt"))
+		require.NoError(t, err)
+		require.Equal(t, expiry, tlsCert.NotAfter.Sub(tlsCert.NotBefore), node)
+	}
+
+	conf.PeerOrgs[0].CA.Expiry = -time.Hour
+	err = Generate(t.TempDir(), conf)
+	require.ErrorContains(t, err, "organization 'Org1' has a negative expiry -1h0m0s for the CA")
+}
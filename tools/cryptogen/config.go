@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package cryptogen
 
 import (
+	"time"
+
 	"github.com/cockroachdb/errors"
 	"go.yaml.in/yaml/v3"
 )
@@ -27,6 +29,19 @@ type OrgSpec struct {
 	Template      NodeTemplate `yaml:"Template"`
 	Specs         []NodeSpec   `yaml:"Specs"`
 	Users         UsersSpec    `yaml:"Users"`
+	// EmitDER writes a DER-encoded (.der) copy next to each generated PEM certificate.
+	EmitDER bool `yaml:"EmitDER"`
+	// CommonSANs are added to the SANS of every node of the organization.
+	CommonSANs []string `yaml:"CommonSANs"`
+	// CAOnly generates only the organization's CAs and verifying MSP, without nodes or users.
+	// They may be added later with Extend.
+	CAOnly bool `yaml:"CAOnly"`
+	// ExportPKCS12 also exports the key and certificate of each node and user as a PKCS#12 bundle,
+	// protected with the password given in the PKCS12PasswordEnv environment variable.
+	ExportPKCS12 bool `yaml:"ExportPKCS12"`
+	// OCSPResponder also generates an OCSP responder certificate for each of the organization's CAs,
+	// signed by that CA, under the ocsp directory.
+	OCSPResponder bool `yaml:"OCSPResponder"`
 }
 
 // NodeSpec represents a certificate specification for a node.
@@ -41,7 +56,29 @@ type NodeSpec struct {
 	PostalCode         string   `yaml:"PostalCode"`
 	SANS               []string `yaml:"SANS"`
 	PublicKeyAlgorithm string   `yaml:"PublicKeyAlgorithm"`
+	ECDSACurve         string   `yaml:"ECDSACurve"`
 	Party              string   `yaml:"Party"`
+	// MaxPathLen sets the basic-constraints path length of a CA, that is, how many intermediate CAs may follow it.
+	// It only applies to the CA spec of an organization. When unset, the path length is not constrained.
+	MaxPathLen *int `yaml:"MaxPathLen"`
+	// AlternateNames are the SANs of the CA certificate. It only applies to the CA spec of an organization.
+	// Unlike SANS, the names are used verbatim, and the CN and the hostname are not implied.
+	AlternateNames []string `yaml:"AlternateNames"`
+	// Email is added as an email SAN to the certificates of the CAs, or to the signing certificate of the node.
+	Email string `yaml:"Email"`
+	// EmailSANs and URISANs are added to the TLS certificate of the node, along with the SANS.
+	// Like the SANS, they accept the template variables {{.Hostname}}, {{.Domain}} and {{.CommonName}}.
+	EmailSANs []string `yaml:"EmailSANs"`
+	URISANs   []string `yaml:"URISANs"`
+	// Expiry is the validity period of the certificates of the CAs, or of the node, e.g., 8760h.
+	// When unset, the certificates are valid for around 10 years.
+	Expiry time.Duration `yaml:"Expiry"`
+	// NotBefore overrides the start of the validity period of the certificates.
+	// When unset, they are valid from 5 minutes before their generation.
+	NotBefore *time.Time `yaml:"NotBefore"`
+	// SerialNumberStrategy selects how the CA issues serial numbers: "random" (default) or "monotonic".
+	// It only applies to the CA spec of an organization.
+	SerialNumberStrategy string `yaml:"SerialNumberStrategy"`
 }
 
 // NodeTemplate represents a template to generate node(s).
@@ -51,19 +88,27 @@ type NodeTemplate struct {
 	Hostname           string   `yaml:"Hostname"`
 	SANS               []string `yaml:"SANS"`
 	PublicKeyAlgorithm string   `yaml:"PublicKeyAlgorithm"`
+	ECDSACurve         string   `yaml:"ECDSACurve"`
 }
 
 // UsersSpec represents a user(s) specification.
 type UsersSpec struct {
 	Count              int        `yaml:"Count"`
 	PublicKeyAlgorithm string     `yaml:"PublicKeyAlgorithm"`
+	ECDSACurve         string     `yaml:"ECDSACurve"`
 	Specs              []UserSpec `yaml:"Specs"`
+	// TrustAnchors are PEM CA certificate files, in addition to the org's CA,
+	// that imported user certificates may chain to.
+	TrustAnchors []string `yaml:"TrustAnchors"`
 }
 
 // UserSpec Contains User specifications needed to customize the crypto material generation.
+// If CertFile is set, the user's certificate and private key (KeyFile) are imported instead of being generated.
 type UserSpec struct {
 	Name               string `yaml:"Name"`
 	PublicKeyAlgorithm string `yaml:"PublicKeyAlgorithm"`
+	CertFile           string `yaml:"CertFile"`
+	KeyFile            string `yaml:"KeyFile"`
 }
 
 // ParseConfig parses config data from string.
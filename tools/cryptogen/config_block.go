@@ -51,6 +51,8 @@ type Node struct {
 	CommonName string
 	Hostname   string
 	SANS       []string
+	EmailSANs  []string
+	URISANs    []string
 	// Fabric-X supports multiple parties per organizations.
 	// Thus, in such case, we can create multiple Orderer's nodes
 	// for each organization.
@@ -197,36 +199,30 @@ func orgOU(o *OrganizationParameters) string {
 	}
 }
 
+func nodeSpecFromNode(n *Node, orgUnit string) NodeSpec {
+	return NodeSpec{
+		CommonName:         n.CommonName,
+		Hostname:           n.Hostname,
+		SANS:               n.SANS,
+		EmailSANs:          n.EmailSANs,
+		URISANs:            n.URISANs,
+		Party:              n.PartyName,
+		OrganizationalUnit: orgUnit,
+	}
+}
+
 func createOrgSpec(o *OrganizationParameters) OrgSpec {
 	ordererNodeCount := len(o.ConsenterNodes) + len(o.OrdererNodes)
 	peerNodeCount := len(o.PeerNodes)
 	nodeSpecs := make([]NodeSpec, 0, ordererNodeCount+peerNodeCount)
-	for _, n := range o.ConsenterNodes {
-		nodeSpecs = append(nodeSpecs, NodeSpec{
-			CommonName:         n.CommonName,
-			Hostname:           n.Hostname,
-			SANS:               n.SANS,
-			Party:              n.PartyName,
-			OrganizationalUnit: OrdererOU,
-		})
+	for i := range o.ConsenterNodes {
+		nodeSpecs = append(nodeSpecs, nodeSpecFromNode(&o.ConsenterNodes[i], OrdererOU))
 	}
-	for _, n := range o.OrdererNodes {
-		nodeSpecs = append(nodeSpecs, NodeSpec{
-			CommonName:         n.CommonName,
-			Hostname:           n.Hostname,
-			SANS:               n.SANS,
-			Party:              n.PartyName,
-			OrganizationalUnit: OrdererOU,
-		})
+	for i := range o.OrdererNodes {
+		nodeSpecs = append(nodeSpecs, nodeSpecFromNode(&o.OrdererNodes[i], OrdererOU))
 	}
-	for _, n := range o.PeerNodes {
-		nodeSpecs = append(nodeSpecs, NodeSpec{
-			CommonName:         n.CommonName,
-			Hostname:           n.Hostname,
-			SANS:               n.SANS,
-			Party:              n.PartyName,
-			OrganizationalUnit: PeerOU,
-		})
+	for i := range o.PeerNodes {
+		nodeSpecs = append(nodeSpecs, nodeSpecFromNode(&o.PeerNodes[i], PeerOU))
 	}
 
 	return OrgSpec{
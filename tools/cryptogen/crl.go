@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptogen
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Certificate revocation lists.
+const (
+	CRLsDir = "crls"
+	CRLFile = "crl.pem"
+	CRLType = "X509 CRL"
+)
+
+// GenerateCRL returns a DER-encoded CRL, signed by the CA stored in caDir, that revokes the given certificates.
+// The certificates must have been issued by that CA.
+func GenerateCRL(caDir string, revokedCerts []*x509.Certificate) ([]byte, error) {
+	ca, err := loadCA(caDir, &OrgSpec{}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	entries := make([]x509.RevocationListEntry, len(revokedCerts))
+	for i, cert := range revokedCerts {
+		if err = cert.CheckSignatureFrom(ca.SignCert); err != nil {
+			return nil, errors.Wrapf(err, "certificate %s was not issued by the CA", cert.Subject.CommonName)
+		}
+		entries[i] = x509.RevocationListEntry{SerialNumber: cert.SerialNumber, RevocationTime: now}
+	}
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		RevokedCertificateEntries: entries,
+		// Like the monotonic serial numbers, the CRL numbers increase with the generation time.
+		Number:     big.NewInt(now.UnixNano()),
+		ThisUpdate: now,
+		NextUpdate: now.Add(defaultExpiry),
+	}, ca.SignCert, ca.Signer)
+	return crl, errors.Wrap(err, "failed to create CRL")
+}
+
+// Revoke revokes the signing certificate of the node or user with the given common name, in the organization
+// stored in orgDir. The CRL, signed by the organization's signing CA, also revokes the certificates revoked
+// before, and is written to the crls folder of all the MSPs of the organization.
+func Revoke(orgDir, commonName string) error {
+	orgMSP := newMspTree(orgDir)
+	certPath := x509FilePath(orgMSP.KnownCerts, commonName)
+	cert, err := loadCertificateFile(certPath)
+	if err != nil {
+		return errors.Wrapf(err, "unknown node or user %s", commonName)
+	}
+
+	revoked, err := loadRevokedCertificates(orgMSP)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(revoked, cert.Equal) {
+		revoked = append(revoked, cert)
+	}
+	crl, err := GenerateCRL(filepath.Join(orgDir, CaDir), revoked)
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(orgDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || d.Name() != MSPDir {
+			return err
+		}
+		crlsDir := filepath.Join(p, CRLsDir)
+		if err = os.MkdirAll(crlsDir, 0o750); err != nil {
+			return errors.Wrapf(err, "cannot create directory %s", crlsDir)
+		}
+		return writePEM(filepath.Join(crlsDir, CRLFile), CRLType, crl)
+	})
+}
+
+// loadRevokedCertificates returns the known certificates of the organization revoked by its current CRL, if any.
+func loadRevokedCertificates(orgMSP *mspTree) ([]*x509.Certificate, error) {
+	crlPath := filepath.Join(orgMSP.MSP, CRLsDir, CRLFile)
+	if _, err := os.Stat(crlPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+	block, err := decodePemFile(crlPath, CRLType)
+	if err != nil {
+		return nil, err
+	}
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "wrong DER encoding [%s]", crlPath)
+	}
+	revokedSerials := make(map[string]struct{}, len(crl.RevokedCertificateEntries))
+	for _, entry := range crl.RevokedCertificateEntries {
+		revokedSerials[entry.SerialNumber.String()] = struct{}{}
+	}
+
+	entries, err := os.ReadDir(orgMSP.KnownCerts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read known certificates [%s]", orgMSP.KnownCerts)
+	}
+	var revoked []*x509.Certificate
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), CertSuffix) {
+			continue
+		}
+		cert, err := loadCertificateFile(filepath.Join(orgMSP.KnownCerts, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := revokedSerials[cert.SerialNumber.String()]; ok {
+			revoked = append(revoked, cert)
+		}
+	}
+	return revoked, nil
+}
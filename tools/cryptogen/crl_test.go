@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptogen
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-x-common/api/msppb"
+	"github.com/hyperledger/fabric-x-common/msp"
+)
+
+func TestRevoke(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	conf := peerOrgConfig(true)
+	conf.PeerOrgs[0].Specs = []NodeSpec{{Hostname: "peer0"}, {Hostname: "peer1"}, {Hostname: "peer2"}}
+	require.NoError(t, Generate(testDir, conf))
+
+	orgPath := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com")
+	peerDir := func(name string) string {
+		return filepath.Join(orgPath, PeerNodesDir, name+".peer-org.com")
+	}
+	// requireValid checks the validity of the peers' identities, with the local MSP of peer1.
+	requireValid := func(t *testing.T, expected map[string]bool) {
+		t.Helper()
+		localMsp, err := msp.LoadLocalMspDir(msp.DirLoadParameters{MspDir: filepath.Join(peerDir("peer1"), MSPDir)})
+		require.NoError(t, err)
+		mspID, err := localMsp.GetIdentifier()
+		require.NoError(t, err)
+		for name, valid := range expected {
+			certPEM, err := os.ReadFile(x509FilePath(peerDir(name), MSPDir, SignCertsDir, name+".peer-org.com"))
+			require.NoError(t, err)
+			id, err := localMsp.DeserializeIdentity(msppb.NewIdentity(mspID, certPEM))
+			require.NoError(t, err)
+			if valid {
+				require.NoError(t, id.Validate(), name)
+			} else {
+				require.ErrorContains(t, id.Validate(), "The certificate has been revoked", name)
+			}
+		}
+	}
+
+	requireValid(t, map[string]bool{"peer0": true, "peer1": true, "peer2": true})
+
+	require.NoError(t, Revoke(orgPath, "peer0.peer-org.com"))
+	require.FileExists(t, filepath.Join(orgPath, MSPDir, CRLsDir, CRLFile))
+	requireValid(t, map[string]bool{"peer0": false, "peer1": true, "peer2": true})
+
+	// Revoking another identity keeps the previous ones revoked.
+	require.NoError(t, Revoke(orgPath, "peer2.peer-org.com"))
+	require.NoError(t, Revoke(orgPath, "peer2.peer-org.com"))
+	requireValid(t, map[string]bool{"peer0": false, "peer1": true, "peer2": false})
+
+	// The organization's verifying MSP picks up the CRL as well.
+	_, err := msp.LoadVerifyingMspDir(msp.DirLoadParameters{MspDir: filepath.Join(orgPath, MSPDir)})
+	require.NoError(t, err)
+
+	err = Revoke(orgPath, "unknown.peer-org.com")
+	require.ErrorContains(t, err, "unknown node or user unknown.peer-org.com")
+}
+
+func TestGenerateCRL(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	rootCA := defaultCA(t, caTestCAName, filepath.Join(testDir, "ca"))
+	otherCA := defaultCA(t, caTestCA2Name, filepath.Join(testDir, "other"))
+
+	_, err := GenerateCRL(filepath.Join(testDir, "ca"), []*x509.Certificate{otherCA.SignCert})
+	require.ErrorContains(t, err, "certificate root1 was not issued by the CA")
+
+	crlBytes, err := GenerateCRL(filepath.Join(testDir, "ca"), []*x509.Certificate{rootCA.SignCert})
+	require.NoError(t, err)
+	crl, err := x509.ParseRevocationList(crlBytes)
+	require.NoError(t, err)
+	require.NoError(t, crl.CheckSignatureFrom(rootCA.SignCert))
+	require.Len(t, crl.RevokedCertificateEntries, 1)
+	require.Equal(t, rootCA.SignCert.SerialNumber, crl.RevokedCertificateEntries[0].SerialNumber)
+}
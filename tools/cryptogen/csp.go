@@ -12,6 +12,7 @@ import (
 	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/asn1"
 	"encoding/pem"
@@ -23,12 +24,22 @@ import (
 	"strings"
 
 	"github.com/cockroachdb/errors"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 // Common constants.
 const (
 	ECDSA   = "ecdsa"
 	ED25519 = "ed25519"
+	// RSA is an alias of RSA2048. RSA keys are only supported for CAs, as the MSP accepts RSA CAs,
+	// but not RSA signing identities.
+	RSA     = "rsa"
+	RSA2048 = "rsa2048"
+	RSA4096 = "rsa4096"
+
+	P256 = "P256"
+	P384 = "P384"
+	P521 = "P521"
 
 	CertType       = "CERTIFICATE"
 	PrivateKeyType = "PRIVATE KEY"
@@ -37,16 +48,30 @@ const (
 	PrivateKeyFile   = "priv" + PrivateKeySuffix
 	CertFileExt      = ".pem"
 	CertSuffix       = "-cert" + CertFileExt
+	DERFileExt       = ".der"
+	PKCS12FileExt    = ".p12"
+
+	// PKCS12PasswordEnv is the environment variable holding the password of the exported PKCS#12 bundles.
+	PKCS12PasswordEnv = "CRYPTOGEN_PKCS12_PASSWORD"
 )
 
-// generatePrivateKey creates an ecdsa private key using a P-256 curve or an ed25519 key
-// and stores it in keystorePath.
-func generatePrivateKey(keystorePath, keyAlg string) (priv crypto.PrivateKey, err error) {
+// generatePrivateKey creates an ecdsa private key using the given curve (P-256 by default),
+// an ed25519 key, or an RSA key of 2048 or 4096 bits, and stores it in keystorePath.
+func generatePrivateKey(keystorePath, keyAlg, curveName string) (priv crypto.PrivateKey, err error) {
 	switch keyAlg {
 	case ECDSA:
-		priv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		var curve elliptic.Curve
+		curve, err = ecdsaCurve(curveName)
+		if err != nil {
+			return nil, err
+		}
+		priv, err = ecdsa.GenerateKey(curve, rand.Reader)
 	case ED25519:
 		_, priv, err = ed25519.GenerateKey(rand.Reader)
+	case RSA, RSA2048:
+		priv, err = rsa.GenerateKey(rand.Reader, 2048)
+	case RSA4096:
+		priv, err = rsa.GenerateKey(rand.Reader, 4096)
 	default:
 		err = errors.Newf("unsupported key algorithm: %s", keyAlg)
 	}
@@ -63,6 +88,21 @@ func generatePrivateKey(keystorePath, keyAlg string) (priv crypto.PrivateKey, er
 	return priv, writePEM(keyFile, PrivateKeyType, pkcs8Encoded)
 }
 
+// ecdsaCurve returns the elliptic curve matching the given name.
+// An empty name defaults to P-256.
+func ecdsaCurve(curveName string) (elliptic.Curve, error) {
+	switch curveName {
+	case "", P256:
+		return elliptic.P256(), nil
+	case P384:
+		return elliptic.P384(), nil
+	case P521:
+		return elliptic.P521(), nil
+	default:
+		return nil, errors.Newf("unsupported ECDSA curve: %s", curveName)
+	}
+}
+
 // loadPrivateKey loads a private key from a file in keystorePath.  It looks
 // for a file ending in "_sk" and expects a PEM-encoded PKCS8 EC private key.
 func loadPrivateKey(keystorePath string) (crypto.PrivateKey, error) {
@@ -70,18 +110,30 @@ func loadPrivateKey(keystorePath string) (crypto.PrivateKey, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parsePrivateKey(keyPath, block)
+}
+
+// loadPrivateKeyFile loads a PEM-encoded PKCS8 private key from the given file.
+func loadPrivateKeyFile(keyPath string) (crypto.PrivateKey, error) {
+	block, err := decodePemFile(keyPath, PrivateKeyType)
+	if err != nil {
+		return nil, err
+	}
+	return parsePrivateKey(keyPath, block)
+}
 
+func parsePrivateKey(keyPath string, block *pem.Block) (crypto.PrivateKey, error) {
 	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
 	if err != nil {
 		return nil, errors.Wrapf(err, "PEM bytes are not PKCS8 encoded [%s]", keyPath)
 	}
 
-	_, isEcdsa := key.(*ecdsa.PrivateKey)
-	_, isEd25519 := key.(ed25519.PrivateKey)
-	if !isEcdsa && !isEd25519 {
-		return nil, errors.Errorf("PEM bytes do not contain an ECDSA nor ed25519 private key [%s]", keyPath)
+	switch key.(type) {
+	case *ecdsa.PrivateKey, ed25519.PrivateKey, *rsa.PrivateKey:
+		return key, nil
+	default:
+		return nil, errors.Errorf("PEM bytes do not contain an ECDSA, ed25519, nor RSA private key [%s]", keyPath)
 	}
-	return key, nil
 }
 
 // loadCertificate load an ECDSA cert from a file in cert path.
@@ -95,6 +147,16 @@ func loadCertificate(certPath string) (*x509.Certificate, error) {
 	return cert, errors.Wrapf(err, "wrong DER encoding [%s]", certPath)
 }
 
+// loadCertificateFile loads a PEM-encoded cert from the given file.
+func loadCertificateFile(certPath string) (*x509.Certificate, error) {
+	block, err := decodePemFile(certPath, CertType)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	return cert, errors.Wrapf(err, "wrong DER encoding [%s]", certPath)
+}
+
 func findAndDecodePem(pemDirPath, suffix, blockType string) (
 	retPath string, block *pem.Block, err error,
 ) {
@@ -102,16 +164,9 @@ func findAndDecodePem(pemDirPath, suffix, blockType string) (
 		if dir.IsDir() || !strings.HasSuffix(curPath, suffix) {
 			return nil
 		}
-		rawPEM, readErr := os.ReadFile(curPath)
-		if readErr != nil {
-			return errors.Wrapf(readErr, "failed to read PEM file [%s]", curPath)
-		}
-		curBlock, _ := pem.Decode(rawPEM)
-		if curBlock == nil {
-			return errors.Errorf("bytes are not PEM encoded [%s]", curPath)
-		}
-		if curBlock.Type != blockType {
-			return errors.Errorf("wrong PEM encoding [%s]", curPath)
+		curBlock, decodeErr := decodePemFile(curPath, blockType)
+		if decodeErr != nil {
+			return decodeErr
 		}
 		block = curBlock
 		retPath = curPath
@@ -126,14 +181,63 @@ func findAndDecodePem(pemDirPath, suffix, blockType string) (
 	return retPath, block, err
 }
 
+func decodePemFile(filePath, blockType string) (*pem.Block, error) {
+	rawPEM, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read PEM file [%s]", filePath)
+	}
+	block, _ := pem.Decode(rawPEM)
+	if block == nil {
+		return nil, errors.Errorf("bytes are not PEM encoded [%s]", filePath)
+	}
+	if block.Type != blockType {
+		return nil, errors.Errorf("wrong PEM encoding [%s]", filePath)
+	}
+	return block, nil
+}
+
 func x509FilePath(name ...string) string {
 	return path.Join(name...) + CertSuffix
 }
 
+// derFilePath returns the path of the DER-encoded copy of a certificate.
+func derFilePath(name ...string) string {
+	return path.Join(name...) + "-cert" + DERFileExt
+}
+
 func writeCert(outputPath string, cert *x509.Certificate) error {
 	return writePEM(outputPath, CertType, cert.Raw)
 }
 
+func writeDER(outputPath string, bytes []byte) error {
+	err := os.WriteFile(outputPath, bytes, 0o600)
+	return errors.Wrapf(err, "failed to save DER to file [%s]", outputPath)
+}
+
+// writePKCS12 writes a password-protected PKCS#12 bundle of a private key, its certificate and the issuer's certificate.
+func writePKCS12(outputPath string, key crypto.PrivateKey, cert, issuer *x509.Certificate, password string) error {
+	pfx, err := pkcs12.Modern.Encode(key, cert, []*x509.Certificate{issuer}, password)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode PKCS#12 bundle")
+	}
+	err = os.WriteFile(outputPath, pfx, 0o600)
+	return errors.Wrapf(err, "failed to save PKCS#12 bundle to file [%s]", outputPath)
+}
+
+// pkcs12Password returns the password of the PKCS#12 bundles of an organization,
+// or an empty string if the organization does not export them.
+func pkcs12Password(spec *OrgSpec) (string, error) {
+	if !spec.ExportPKCS12 {
+		return "", nil
+	}
+	password := os.Getenv(PKCS12PasswordEnv)
+	if password == "" {
+		return "", errors.Newf("%s must be set to export PKCS#12 bundles of organization %s",
+			PKCS12PasswordEnv, spec.Name)
+	}
+	return password, nil
+}
+
 func writePEM(outputPath, pemType string, bytes []byte) error {
 	pemEncoded := pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: bytes})
 	err := os.WriteFile(outputPath, pemEncoded, 0o600)
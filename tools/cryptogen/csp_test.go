@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package cryptogen
 
 import (
+	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
@@ -26,7 +27,7 @@ import (
 func TestLoadPrivateKey(t *testing.T) {
 	t.Parallel()
 	testDir := t.TempDir()
-	priv, err := generatePrivateKey(testDir, ED25519)
+	priv, err := generatePrivateKey(testDir, ED25519, "")
 	require.NoError(t, err, "failed to generate private key")
 	pkFile := filepath.Join(testDir, "priv_sk")
 	require.FileExists(t, pkFile, "Expected to find private key file")
@@ -42,9 +43,11 @@ func TestLoadPrivateKey_BadPEM(t *testing.T) {
 	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
 	require.NoError(t, err, "failed to generate RSA key")
 
-	pkcs8Encoded, err := x509.MarshalPKCS8PrivateKey(rsaKey)
-	require.NoError(t, err, "failed to PKCS8 encode RSA private key")
-	pkcs8RSAPem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Encoded})
+	x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err, "failed to generate X25519 key")
+	pkcs8Encoded, err := x509.MarshalPKCS8PrivateKey(x25519Key)
+	require.NoError(t, err, "failed to PKCS8 encode X25519 private key")
+	pkcs8X25519Pem := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Encoded})
 
 	pkcs1Encoded := x509.MarshalPKCS1PrivateKey(rsaKey)
 	require.NotNil(t, pkcs1Encoded, "failed to PKCS1 encode RSA private key")
@@ -61,9 +64,9 @@ func TestLoadPrivateKey_BadPEM(t *testing.T) {
 			errMsg: "bytes are not PEM encoded",
 		},
 		{
-			name:   "not EC key",
-			data:   pkcs8RSAPem,
-			errMsg: "PEM bytes do not contain an ECDSA nor ed25519 private key",
+			name:   "unsupported key",
+			data:   pkcs8X25519Pem,
+			errMsg: "PEM bytes do not contain an ECDSA, ed25519, nor RSA private key",
 		},
 		{
 			name:   "not PKCS8 encoded",
@@ -94,12 +97,12 @@ func TestGeneratePrivateKey(t *testing.T) {
 	testDir := t.TempDir()
 
 	expectedFile := filepath.Join(testDir, "priv_sk")
-	priv, err := generatePrivateKey(testDir, ECDSA)
+	priv, err := generatePrivateKey(testDir, ECDSA, "")
 	require.NoError(t, err, "Failed to generate private key")
 	require.NotNil(t, priv, "Should have returned an *ecdsa.Key")
 	require.FileExists(t, expectedFile, "Expected to find private key file")
 
-	_, err = generatePrivateKey("notExist", ECDSA)
+	_, err = generatePrivateKey("notExist", ECDSA, "")
 	require.Contains(t, err.Error(), "no such file or directory")
 }
 
@@ -147,3 +150,33 @@ func TestED25519Signer(t *testing.T) {
 	ok := ed25519.Verify(pub, msg, sig)
 	require.True(t, ok, "Expected valid signature")
 }
+
+func TestGeneratePrivateKeyECDSACurve(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		curveName string
+		curve     elliptic.Curve
+	}{
+		{curveName: "", curve: elliptic.P256()},
+		{curveName: P256, curve: elliptic.P256()},
+		{curveName: P384, curve: elliptic.P384()},
+		{curveName: P521, curve: elliptic.P521()},
+	} {
+		t.Run(tc.curveName, func(t *testing.T) {
+			t.Parallel()
+			testDir := t.TempDir()
+			privGeneric, err := generatePrivateKey(testDir, ECDSA, tc.curveName)
+			require.NoError(t, err)
+			priv, ok := privGeneric.(*ecdsa.PrivateKey)
+			require.True(t, ok)
+			require.Equal(t, tc.curve, priv.Curve)
+
+			loadedPriv, err := loadPrivateKey(testDir)
+			require.NoError(t, err)
+			require.Equal(t, priv, loadedPriv)
+		})
+	}
+
+	_, err := generatePrivateKey(t.TempDir(), ECDSA, "P224")
+	require.EqualError(t, err, "unsupported ECDSA curve: P224")
+}
@@ -10,9 +10,11 @@ import (
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/rsa"
 	"crypto/x509"
 	"os"
 	"path"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"go.yaml.in/yaml/v3"
@@ -39,9 +41,19 @@ type nodeParameters struct {
 	TLSCa     *caParams
 	TLSSans   []string
 	Name      string
+	Email     string
 	OU        string
 	EnableOUs bool
 	KeyAlg    string
+	Curve     string
+	// TLSEmailSANs and TLSURISANs are the email and URI SANs of the TLS certificate.
+	TLSEmailSANs []string
+	TLSURISANs   []string
+	// Expiry and NotBefore set the validity period of the node's certificates. Zero values select the defaults.
+	Expiry    time.Duration
+	NotBefore *time.Time
+	// PKCS12Password, if set, exports the identity as a PKCS#12 bundle protected with it.
+	PKCS12Password string
 }
 
 // Directories.
@@ -110,30 +122,20 @@ func (t *mspTree) generateVerifyingMSP(p nodeParameters) error {
 	// Key-store and sign-certificates are not applicable to the verifying MSP.
 	defer removeAllFolders(t.KeyStore, t.SignCerts)
 	p.Name = p.SignCa.Name
+	p.Email = ""
+	p.PKCS12Password = ""
 	return t.generateMsp(p)
 }
 
 // generateMsp generates a generic MSP.
 func (t *mspTree) generateMsp(p nodeParameters) error {
-	// Note: "admincerts" and "knowncerts" are populated by the caller.
-	err := createAllFolders(t.CaCerts, t.TLSCaCerts, t.AdminCerts, t.KeyStore, t.SignCerts, t.KnownCerts)
-	if err != nil {
-		return err
-	}
-
-	// the signing CA certificate goes into cacerts.
-	err = writeCert(x509FilePath(t.CaCerts, p.SignCa.Name), p.SignCa.SignCert)
-	if err != nil {
-		return err
-	}
-	// the TLS CA certificate goes into tlscacerts.
-	err = writeCert(x509FilePath(t.TLSCaCerts, p.TLSCa.Name), p.TLSCa.SignCert)
+	err := t.createMspFolders(p)
 	if err != nil {
 		return err
 	}
 
 	// generate private key.
-	priv, err := generatePrivateKey(t.KeyStore, p.KeyAlg)
+	priv, err := generatePrivateKey(t.KeyStore, p.KeyAlg, p.Curve)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate private key")
 	}
@@ -141,37 +143,109 @@ func (t *mspTree) generateMsp(p nodeParameters) error {
 	// generate X509 certificate using signing CA.
 	cert, err := p.SignCa.signCertificate(t.SignCerts, p.Name, signCertParams{
 		OrgUnits:    []string{p.OU},
+		Email:       p.Email,
 		KeyUsage:    x509.KeyUsageDigitalSignature,
 		ExtKeyUsage: []x509.ExtKeyUsage{},
 		PublicKey:   getPublicKey(priv),
+		Expiry:      p.Expiry,
+		NotBefore:   p.NotBefore,
 	})
 	if err != nil {
 		return err
 	}
+	err = t.exportPKCS12(p, priv, cert, p.SignCa.SignCert)
+	if err != nil {
+		return err
+	}
 
-	if p.EnableOUs {
-		// generate config.yaml if required.
-		err = exportConfig(t.MSP, x509FilePath(CACertsDir, p.SignCa.Name), true)
-		if err != nil {
-			return err
-		}
-	} else {
-		// the signing identity goes into admincerts.
-		// This means that the signing identity
-		// of this MSP is also an admin of this MSP
-		// NOTE: For an organization verifying MSP, the admincerts folder
-		// is going to be cleared up and be overwritten with its admin user folder.
-		// However, we leave it for now for the sake of unit tests.
-		err = writeCert(x509FilePath(t.AdminCerts, p.Name), cert)
+	return t.exportIdentityConfig(p, cert)
+}
+
+// importLocalMSP generates a local MSP around an existing identity instead of minting one.
+// The TLS artifacts are generated as usual.
+func (t *mspTree) importLocalMSP(p nodeParameters, id *importedIdentity) error {
+	// Known-certs are not applicable to the local MSP.
+	defer removeAllFolders(t.KnownCerts)
+	err := t.createMspFolders(p)
+	if err != nil {
+		return err
+	}
+
+	// an external trust anchor goes into cacerts alongside the signing CA certificate.
+	if !id.issuer.Equal(p.SignCa.SignCert) {
+		err = writeCert(x509FilePath(t.CaCerts, id.issuer.Subject.CommonName), id.issuer)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	pkcs8Encoded, err := x509.MarshalPKCS8PrivateKey(id.key)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal private key")
+	}
+	err = writePEM(path.Join(t.KeyStore, PrivateKeyFile), PrivateKeyType, pkcs8Encoded)
+	if err != nil {
+		return err
+	}
+	err = writeCert(x509FilePath(t.SignCerts, p.Name), id.cert)
+	if err != nil {
+		return err
+	}
+	err = t.exportPKCS12(p, id.key, id.cert, id.issuer)
+	if err != nil {
+		return err
+	}
+
+	err = t.exportIdentityConfig(p, id.cert)
+	if err != nil {
+		return err
+	}
+	return t.generateTLS(p)
+}
+
+// exportPKCS12 writes the identity's PKCS#12 bundle next to its MSP folder, if requested.
+func (t *mspTree) exportPKCS12(p nodeParameters, key crypto.PrivateKey, cert, issuer *x509.Certificate) error {
+	if p.PKCS12Password == "" {
+		return nil
+	}
+	return writePKCS12(path.Join(t.Root, p.Name+PKCS12FileExt), key, cert, issuer, p.PKCS12Password)
+}
+
+// createMspFolders creates the MSP folders and populates the CA certificates.
+func (t *mspTree) createMspFolders(p nodeParameters) error {
+	// Note: "admincerts" and "knowncerts" are populated by the caller.
+	err := createAllFolders(t.CaCerts, t.TLSCaCerts, t.AdminCerts, t.KeyStore, t.SignCerts, t.KnownCerts)
+	if err != nil {
+		return err
+	}
+
+	// the signing CA certificate goes into cacerts.
+	err = writeCert(x509FilePath(t.CaCerts, p.SignCa.Name), p.SignCa.SignCert)
+	if err != nil {
+		return err
+	}
+	// the TLS CA certificate goes into tlscacerts.
+	return writeCert(x509FilePath(t.TLSCaCerts, p.TLSCa.Name), p.TLSCa.SignCert)
+}
+
+// exportIdentityConfig generates config.yaml if NodeOUs are enabled, or adds the signing identity to admincerts.
+func (t *mspTree) exportIdentityConfig(p nodeParameters, cert *x509.Certificate) error {
+	if p.EnableOUs {
+		// generate config.yaml if required.
+		return exportConfig(t.MSP, x509FilePath(CACertsDir, p.SignCa.Name), true)
+	}
+	// the signing identity goes into admincerts.
+	// This means that the signing identity
+	// of this MSP is also an admin of this MSP
+	// NOTE: For an organization verifying MSP, the admincerts folder
+	// is going to be cleared up and be overwritten with its admin user folder.
+	// However, we leave it for now for the sake of unit tests.
+	return writeCert(x509FilePath(t.AdminCerts, p.Name), cert)
 }
 
 // generateTLS generates the TLS artifacts in the TLS folder.
+// Users, including admins, get a client certificate (client.crt), and nodes a server certificate (server.crt).
+// Both have their own key, distinct from the signing key, and are valid for client and server authentication.
 func (t *mspTree) generateTLS(p nodeParameters) error {
 	err := createAllFolders(t.TLS)
 	if err != nil {
@@ -179,7 +253,7 @@ func (t *mspTree) generateTLS(p nodeParameters) error {
 	}
 
 	// generate private key.
-	tlsPrivKey, err := generatePrivateKey(t.TLS, p.KeyAlg)
+	tlsPrivKey, err := generatePrivateKey(t.TLS, p.KeyAlg, p.Curve)
 	if err != nil {
 		return err
 	}
@@ -187,12 +261,16 @@ func (t *mspTree) generateTLS(p nodeParameters) error {
 	// generate X509 certificate using TLS CA.
 	_, err = p.TLSCa.signCertificate(t.TLS, p.Name, signCertParams{
 		AlternateNames: p.TLSSans,
+		EmailSANs:      p.TLSEmailSANs,
+		URISANs:        p.TLSURISANs,
 		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
 		ExtKeyUsage: []x509.ExtKeyUsage{
 			x509.ExtKeyUsageServerAuth,
 			x509.ExtKeyUsageClientAuth,
 		},
 		PublicKey: getPublicKey(tlsPrivKey),
+		Expiry:    p.Expiry,
+		NotBefore: p.NotBefore,
 	})
 	if err != nil {
 		return err
@@ -214,6 +292,12 @@ func (t *mspTree) generateTLS(p nodeParameters) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to rename TLS certificate")
 	}
+	if p.TLSCa.EmitDER {
+		err = os.Rename(derFilePath(t.TLS, p.Name), path.Join(t.TLS, tlsFilePrefix+DERFileExt))
+		if err != nil {
+			return errors.Wrap(err, "failed to rename DER TLS certificate")
+		}
+	}
 	err = os.Rename(path.Join(t.TLS, PrivateKeyFile), path.Join(t.TLS, tlsFilePrefix+".key"))
 	if err != nil {
 		return errors.Wrap(err, "failed to rename TLS private key")
@@ -227,6 +311,8 @@ func getPublicKey(priv crypto.PrivateKey) crypto.PublicKey {
 		return &(kk.PublicKey)
 	case ed25519.PrivateKey:
 		return kk.Public()
+	case *rsa.PrivateKey:
+		return &kk.PublicKey
 	default:
 		panic("unsupported key algorithm")
 	}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package cryptogen
 
 import (
+	"crypto/x509"
 	"fmt"
 	"os"
 	"path"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.yaml.in/yaml/v3"
+	"software.sslmate.com/src/go-pkcs12"
 
 	"github.com/hyperledger/fabric-x-common/msp"
 	"github.com/hyperledger/fabric-x-common/tools/test"
@@ -193,3 +195,68 @@ func newMSPParameters(t *testing.T, rootDir, nodeOU string, enableNodeOUs bool)
 		EnableOUs: enableNodeOUs,
 	}
 }
+
+func TestGenerateAdminClientTLS(t *testing.T) {
+	t.Parallel()
+	for _, nodeOUs := range []bool{true, false} {
+		t.Run(fmt.Sprintf("nodeOUs=%t", nodeOUs), func(t *testing.T) {
+			t.Parallel()
+			testDir := t.TempDir()
+			require.NoError(t, Generate(testDir, peerOrgConfig(nodeOUs)))
+
+			orgPath := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com")
+			adminDir := filepath.Join(orgPath, UsersDir, "Admin@peer-org.com")
+			require.FileExists(t, filepath.Join(adminDir, TLSDir, ClientPrefix+".key"))
+			require.NoFileExists(t, filepath.Join(adminDir, TLSDir, ServerPrefix+".crt"))
+			tlsCert, err := loadCertificateFile(filepath.Join(adminDir, TLSDir, ClientPrefix+".crt"))
+			require.NoError(t, err)
+			require.Contains(t, tlsCert.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+
+			// The TLS certificate is issued by the TLS CA, for a key distinct from the signing key.
+			tlsCA, err := loadCertificate(filepath.Join(orgPath, TLSCaDir))
+			require.NoError(t, err)
+			require.NoError(t, tlsCert.CheckSignatureFrom(tlsCA))
+			signCert, err := loadCertificate(filepath.Join(adminDir, MSPDir, SignCertsDir))
+			require.NoError(t, err)
+			require.NotEqual(t, signCert.RawSubjectPublicKeyInfo, tlsCert.RawSubjectPublicKeyInfo)
+		})
+	}
+}
+
+//nolint:paralleltest // t.Setenv does not allow parallel tests.
+func TestGenerateExportPKCS12(t *testing.T) {
+	testDir := t.TempDir()
+	conf := peerOrgConfig(false)
+	conf.PeerOrgs[0].ExportPKCS12 = true
+	conf.PeerOrgs[0].Users.Count = 1
+
+	t.Setenv(PKCS12PasswordEnv, "")
+	err := Generate(testDir, conf)
+	require.ErrorContains(t, err, PKCS12PasswordEnv+" must be set to export PKCS#12 bundles of organization PeerOrg")
+
+	t.Setenv(PKCS12PasswordEnv, "s3cret")
+	testDir = t.TempDir()
+	require.NoError(t, Generate(testDir, conf))
+
+	userDir := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com", UsersDir, "User1@peer-org.com")
+	pfx, err := os.ReadFile(filepath.Join(userDir, "User1@peer-org.com"+PKCS12FileExt))
+	require.NoError(t, err)
+	key, cert, caCerts, err := pkcs12.DecodeChain(pfx, "s3cret")
+	require.NoError(t, err)
+
+	expectedKey, err := loadPrivateKey(filepath.Join(userDir, MSPDir, KeyStoreDir))
+	require.NoError(t, err)
+	require.Equal(t, expectedKey, key)
+	expectedCert, err := loadCertificate(filepath.Join(userDir, MSPDir, SignCertsDir))
+	require.NoError(t, err)
+	require.True(t, expectedCert.Equal(cert))
+	require.Len(t, caCerts, 1)
+	require.Equal(t, "PeerOrgCA", caCerts[0].Subject.CommonName)
+
+	_, _, _, err = pkcs12.DecodeChain(pfx, "wrong")
+	require.Error(t, err)
+	// The verifying MSP of the organization is not exported.
+	matches, err := filepath.Glob(filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com", "*"+PKCS12FileExt))
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
@@ -8,10 +8,15 @@ package cryptogen
 
 import (
 	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"slices"
 
 	"github.com/cockroachdb/errors"
@@ -25,8 +30,43 @@ type orgCryptoTree struct {
 	CA            string
 	Users         string
 	TLSCa         string
+	OCSP          string
 	OrderingNodes string
 	PeerNodes     string
+	// Summary collects the material generated for the organization.
+	Summary OrgSummary
+	// Parallelism is the maximal number of nodes generated concurrently. It defaults to the number of CPUs.
+	Parallelism int
+}
+
+// Summary describes the crypto material generated by cryptogen.
+type Summary struct {
+	Organizations []OrgSummary `json:"organizations"`
+}
+
+// OrgSummary describes the crypto material generated for an organization.
+// The fingerprints are the hex-encoded SHA-256 digests of the DER-encoded CA certificates.
+type OrgSummary struct {
+	Name             string        `json:"name"`
+	Domain           string        `json:"domain"`
+	Dir              string        `json:"dir"`
+	CAFingerprint    string        `json:"ca-fingerprint"`
+	TLSCAFingerprint string        `json:"tls-ca-fingerprint"`
+	Nodes            []NodeSummary `json:"nodes"`
+}
+
+// NodeSummary describes a generated node or user MSP.
+type NodeSummary struct {
+	CommonName string `json:"common-name"`
+	OU         string `json:"ou"`
+	MSPDir     string `json:"msp-dir"`
+}
+
+// importedIdentity is an existing user identity to be placed into the users MSP structure.
+type importedIdentity struct {
+	cert   *x509.Certificate
+	key    crypto.PrivateKey
+	issuer *x509.Certificate
 }
 
 // cryptoTree collects all the generated crypto material.
@@ -48,6 +88,7 @@ const (
 	CaDir                   = "ca"
 	UsersDir                = "users"
 	TLSCaDir                = "tlsca"
+	OCSPDir                 = "ocsp"
 	PeerNodesDir            = "peers"
 	OrdererNodesDir         = "orderers"
 	OrdererOrganizationsDir = "ordererOrganizations"
@@ -55,27 +96,53 @@ const (
 	GenericOrganizationsDir = "organizations"
 
 	TLSCaPrefix = "tls"
+	OCSPPrefix  = "ocsp"
 
 	DefaultCaHostname = "ca"
 )
 
 // Generate generates crypto in the given directory using the given config.
 func Generate(rootDir string, config *Config) error {
+	_, err := GenerateWithSummary(rootDir, config)
+	return err
+}
+
+// GenerateWithSummary generates crypto in the given directory using the given config,
+// and returns a summary of the generated material. Its directories are relative to rootDir.
+func GenerateWithSummary(rootDir string, config *Config) (*Summary, error) {
+	if errs := ValidateConfig(config); len(errs) > 0 {
+		return nil, errors.Wrap(errors.Join(errs...), "invalid config")
+	}
 	c, err := prepareAllCryptoSpecs(rootDir, config)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	trees := allTrees(c)
 	wg, _ := errgroup.WithContext(context.Background())
-	for _, orgTree := range allTrees(c) {
+	for _, orgTree := range trees {
 		wg.Go(func() error {
 			return orgTree.generateOrg()
 		})
 	}
-	return wg.Wait()
+	if err = wg.Wait(); err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{Organizations: make([]OrgSummary, len(trees))}
+	for i, orgTree := range trees {
+		summary.Organizations[i], err = orgTree.relativeSummary(rootDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
 }
 
 // Extend extends a crypto in the given directory using the given config.
 func Extend(rootDir string, config *Config) error {
+	if errs := ValidateConfig(config); len(errs) > 0 {
+		return errors.Wrap(errors.Join(errs...), "invalid config")
+	}
 	c, err := prepareAllCryptoSpecs(rootDir, config)
 	if err != nil {
 		return err
@@ -136,6 +203,7 @@ func newOrgCryptoTree(root string, org *OrgSpec) *orgCryptoTree {
 		CA:            filepath.Join(root, CaDir),
 		Users:         filepath.Join(root, UsersDir),
 		TLSCa:         filepath.Join(root, TLSCaDir),
+		OCSP:          filepath.Join(root, OCSPDir),
 		OrderingNodes: filepath.Join(root, OrdererNodesDir),
 		PeerNodes:     filepath.Join(root, PeerNodesDir),
 	}
@@ -171,26 +239,40 @@ func (c *orgCryptoTree) generateOrg() error {
 	orgName := s.Domain
 
 	// generate signing CA
-	signCA, err := caFromSpec(c.CA, orgName, "", &s.CA)
+	signCA, err := caFromSpec(c.CA, "", s)
 	if err != nil {
 		return err
 	}
 	// generate TLS CA
-	tlsCA, err := caFromSpec(c.TLSCa, orgName, TLSCaPrefix, &s.CA)
+	tlsCA, err := caFromSpec(c.TLSCa, TLSCaPrefix, s)
 	if err != nil {
 		return err
 	}
+	c.Summary = OrgSummary{
+		Name:             s.Name,
+		Domain:           s.Domain,
+		Dir:              c.Root,
+		CAFingerprint:    certFingerprint(signCA.SignCert),
+		TLSCAFingerprint: certFingerprint(tlsCA.SignCert),
+	}
+	if s.OCSPResponder {
+		err = c.generateOCSPResponders(signCA, tlsCA)
+		if err != nil {
+			return err
+		}
+	}
 
-	p := nodeParameters{
-		SignCa:    signCA,
-		TLSCa:     tlsCA,
-		EnableOUs: s.EnableNodeOUs,
-		KeyAlg:    s.CA.PublicKeyAlgorithm,
+	p, err := c.nodeParameters(signCA, tlsCA)
+	if err != nil {
+		return err
 	}
 	err = c.generateVerifyingMSP(p)
 	if err != nil {
 		return err
 	}
+	if s.CAOnly {
+		return nil
+	}
 
 	err = c.generateNodes(s.Specs, p)
 	if err != nil {
@@ -204,6 +286,10 @@ func (c *orgCryptoTree) generateOrg() error {
 	if err != nil {
 		return err
 	}
+	err = c.importUsers(p)
+	if err != nil {
+		return err
+	}
 
 	// copy the admin cert to the org's MSP admincerts.
 	if !s.EnableNodeOUs {
@@ -220,6 +306,41 @@ func (c *orgCryptoTree) generateOrg() error {
 	return nil
 }
 
+// relativeSummary returns the summary of the organization, with its directories relative to rootDir.
+func (c *orgCryptoTree) relativeSummary(rootDir string) (OrgSummary, error) {
+	summary := c.Summary
+	summary.Nodes = slices.Clone(summary.Nodes)
+	dirs := []*string{&summary.Dir}
+	for i := range summary.Nodes {
+		dirs = append(dirs, &summary.Nodes[i].MSPDir)
+	}
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(rootDir, *dir)
+		if err != nil {
+			return OrgSummary{}, errors.Wrapf(err, "cannot resolve %s relatively to %s", *dir, rootDir)
+		}
+		*dir = rel
+	}
+	return summary, nil
+}
+
+// certFingerprint returns the hex-encoded SHA-256 digest of the certificate.
+func certFingerprint(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(digest[:])
+}
+
+// generateOCSPResponders generates an OCSP responder for the signing CA and for the TLS CA,
+// in the respective sub-directories of the OCSP directory.
+func (c *orgCryptoTree) generateOCSPResponders(signCA, tlsCA *caParams) error {
+	_, err := signCA.generateOCSPResponder(filepath.Join(c.OCSP, CaDir))
+	if err != nil {
+		return err
+	}
+	_, err = tlsCA.generateOCSPResponder(filepath.Join(c.OCSP, TLSCaDir))
+	return err
+}
+
 // extendOrg extends the organization's crypto.
 func (c *orgCryptoTree) extendOrg() error {
 	if !c.isExist() {
@@ -227,6 +348,9 @@ func (c *orgCryptoTree) extendOrg() error {
 	}
 
 	s := c.OrgSpec
+	if s.CAOnly {
+		return nil
+	}
 	signCA, err := loadCA(c.CA, s, s.CA.CommonName)
 	if err != nil {
 		return err
@@ -236,24 +360,32 @@ func (c *orgCryptoTree) extendOrg() error {
 		return err
 	}
 
-	p := nodeParameters{
-		SignCa:    signCA,
-		TLSCa:     tlsCA,
-		EnableOUs: s.EnableNodeOUs,
-		KeyAlg:    s.CA.PublicKeyAlgorithm,
+	p, err := c.nodeParameters(signCA, tlsCA)
+	if err != nil {
+		return err
 	}
 	err = c.generateNodes(s.Specs, p)
 	if err != nil {
 		return err
 	}
 
-	err = c.generateNodes(c.generateUsers(), p)
+	// the admin user is missing if the organization was generated CA-only.
+	orgAdminUser := adminUser(s.Domain)
+	err = c.generateNodes(append(c.generateUsers(), orgAdminUser), p)
+	if err != nil {
+		return err
+	}
+	err = c.importUsers(p)
 	if err != nil {
 		return err
 	}
 
 	if !c.OrgSpec.EnableNodeOUs {
-		err = c.overwriteNodesAdminCert(adminUser(s.Domain).CommonName)
+		err = c.overwriteAdminCert(c.AdminCerts, orgAdminUser.CommonName)
+		if err != nil {
+			return err
+		}
+		err = c.overwriteNodesAdminCert(orgAdminUser.CommonName)
 		if err != nil {
 			return err
 		}
@@ -262,15 +394,37 @@ func (c *orgCryptoTree) extendOrg() error {
 	return nil
 }
 
+// nodeParameters returns the parameters shared by all the nodes and users of the organization.
+func (c *orgCryptoTree) nodeParameters(signCA, tlsCA *caParams) (nodeParameters, error) {
+	s := c.OrgSpec
+	password, err := pkcs12Password(s)
+	if err != nil {
+		return nodeParameters{}, err
+	}
+	return nodeParameters{
+		SignCa:         signCA,
+		TLSCa:          tlsCA,
+		EnableOUs:      s.EnableNodeOUs,
+		KeyAlg:         s.CA.PublicKeyAlgorithm,
+		Curve:          s.CA.ECDSACurve,
+		PKCS12Password: password,
+	}, nil
+}
+
 func (c *orgCryptoTree) generateUsers() []NodeSpec {
 	s := c.OrgSpec
 	orgName := s.Domain
 	users := make([]NodeSpec, 0, len(s.Users.Specs)+s.Users.Count)
 	publicKeyAlg := getPublicKeyAlg(s.Users.PublicKeyAlgorithm)
 	for _, spec := range s.Users.Specs {
+		if spec.CertFile != "" {
+			// Imported by importUsers.
+			continue
+		}
 		users = append(users, NodeSpec{
 			CommonName:         fmt.Sprintf("%s@%s", spec.Name, orgName),
 			PublicKeyAlgorithm: publicKeyAlg,
+			ECDSACurve:         s.Users.ECDSACurve,
 			OrganizationalUnit: ClientOU,
 		})
 	}
@@ -278,12 +432,105 @@ func (c *orgCryptoTree) generateUsers() []NodeSpec {
 		users = append(users, NodeSpec{
 			CommonName:         fmt.Sprintf("%s%d@%s", userBaseName, j+1, orgName),
 			PublicKeyAlgorithm: publicKeyAlg,
+			ECDSACurve:         s.Users.ECDSACurve,
 			OrganizationalUnit: ClientOU,
 		})
 	}
 	return users
 }
 
+// importUsers places the provided user certificates into the users MSP structure instead of minting them.
+// Each certificate must chain to the org's signing CA or to one of the users' trust anchors.
+func (c *orgCryptoTree) importUsers(p nodeParameters) error {
+	s := c.OrgSpec
+	roots, err := c.importRoots(p)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range s.Users.Specs {
+		if spec.CertFile == "" {
+			continue
+		}
+		name := fmt.Sprintf("%s@%s", spec.Name, s.Domain)
+		tree := c.subUser(name)
+		if tree.isExist() {
+			continue
+		}
+		id, err := loadImportedIdentity(&spec, roots)
+		if err != nil {
+			return errors.Wrapf(err, "failed to import user %s", name)
+		}
+
+		curParams := p
+		curParams.Name = name
+		curParams.OU = ClientOU
+		curParams.KeyAlg = getPublicKeyAlg(s.Users.PublicKeyAlgorithm)
+		curParams.Curve = s.Users.ECDSACurve
+		err = tree.importLocalMSP(curParams, id)
+		if err != nil {
+			return err
+		}
+		c.Summary.Nodes = append(c.Summary.Nodes, NodeSummary{CommonName: name, OU: ClientOU, MSPDir: tree.MSP})
+
+		// Add certificate to the organization's known certs, and its trust anchor to the organization's CA certs.
+		err = writeCert(x509FilePath(c.KnownCerts, name), id.cert)
+		if err != nil {
+			return err
+		}
+		if !id.issuer.Equal(p.SignCa.SignCert) {
+			err = writeCert(x509FilePath(c.CaCerts, id.issuer.Subject.CommonName), id.issuer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importRoots returns the pool of certificates imported users may chain to.
+func (c *orgCryptoTree) importRoots(p nodeParameters) (*x509.CertPool, error) {
+	roots := x509.NewCertPool()
+	roots.AddCert(p.SignCa.SignCert)
+	for _, anchorPath := range c.OrgSpec.Users.TrustAnchors {
+		anchor, err := loadCertificateFile(anchorPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load trust anchor")
+		}
+		roots.AddCert(anchor)
+	}
+	return roots, nil
+}
+
+// loadImportedIdentity loads a user's certificate and private key, and verifies the certificate chains to roots.
+func loadImportedIdentity(spec *UserSpec, roots *x509.CertPool) (*importedIdentity, error) {
+	if spec.KeyFile == "" {
+		return nil, errors.New("a key file must be provided with the certificate file")
+	}
+	cert, err := loadCertificateFile(spec.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	key, err := loadPrivateKeyFile(spec.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := getPublicKey(key).(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(cert.PublicKey) {
+		return nil, errors.Newf("private key [%s] does not match certificate [%s]", spec.KeyFile, spec.CertFile)
+	}
+
+	chains, err := cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "certificate [%s] does not chain to the org CA or a trust anchor", spec.CertFile)
+	}
+	chain := chains[0]
+	return &importedIdentity{cert: cert, key: key, issuer: chain[len(chain)-1]}, nil
+}
+
 // overwriteNodesAdminCert overwrite the admin cert to each node with the org's MSP admincerts.
 func (c *orgCryptoTree) overwriteNodesAdminCert(orgAdminUserName string) error {
 	for _, spec := range c.OrgSpec.Specs {
@@ -314,37 +561,78 @@ func (c *orgCryptoTree) overwriteAdminCert(adminCertsDir, adminUserName string)
 	return copyFile(src, adminCertPath)
 }
 
+// generateNodes generates the nodes' local MSPs concurrently, with up to Parallelism nodes at once.
+// The CAs' signers and serial number generators are safe for concurrent use,
+// and each node is generated in its own directory.
 func (c *orgCryptoTree) generateNodes(nodes []NodeSpec, p nodeParameters) error {
+	generated := make([]*NodeSummary, len(nodes))
+	seen := make(map[string]struct{}, len(nodes))
+	wg := &errgroup.Group{}
+	wg.SetLimit(c.parallelism())
 	for i := range nodes {
 		node := &nodes[i]
 		tree := c.subNodeFromSpec(node)
-		if tree.isExist() {
+		// A node sharing the directory of a previous node is not generated again.
+		if _, ok := seen[tree.Root]; ok || tree.isExist() {
 			continue
 		}
-		curParams := p
-		curParams.OU = node.OrganizationalUnit
-		if node.OrganizationalUnit == AdminOU && !p.EnableOUs {
-			curParams.OU = ClientOU
-		}
-		curParams.Name = node.CommonName
-		curParams.TLSSans = node.SANS
-		curParams.KeyAlg = node.PublicKeyAlgorithm
-		err := tree.generateLocalMSP(curParams)
-		if err != nil {
-			return err
-		}
-
-		// Add certificate to the organization's known certs.
-		srcCertPath := path.Join(tree.SignCerts, node.CommonName+"-cert.pem")
-		targetCertPath := path.Join(c.KnownCerts, node.CommonName+"-cert.pem")
-		err = copyFile(srcCertPath, targetCertPath)
-		if err != nil {
+		seen[tree.Root] = struct{}{}
+		wg.Go(func() error {
+			summary, err := c.generateNode(node, tree, p)
+			generated[i] = summary
 			return err
+		})
+	}
+	err := wg.Wait()
+	if err != nil {
+		return err
+	}
+	for _, summary := range generated {
+		if summary != nil {
+			c.Summary.Nodes = append(c.Summary.Nodes, *summary)
 		}
 	}
 	return nil
 }
 
+// parallelism returns the maximal number of nodes generated concurrently.
+func (c *orgCryptoTree) parallelism() int {
+	if c.Parallelism > 0 {
+		return c.Parallelism
+	}
+	return runtime.NumCPU()
+}
+
+// generateNode generates the local MSP of a node in the given tree.
+func (c *orgCryptoTree) generateNode(node *NodeSpec, tree *mspTree, p nodeParameters) (*NodeSummary, error) {
+	p.OU = node.OrganizationalUnit
+	if node.OrganizationalUnit == AdminOU && !p.EnableOUs {
+		p.OU = ClientOU
+	}
+	p.Name = node.CommonName
+	p.TLSSans = node.SANS
+	p.TLSEmailSANs = node.EmailSANs
+	p.TLSURISANs = node.URISANs
+	p.Email = node.Email
+	p.KeyAlg = node.PublicKeyAlgorithm
+	p.Curve = node.ECDSACurve
+	p.Expiry = node.Expiry
+	p.NotBefore = node.NotBefore
+	err := tree.generateLocalMSP(p)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add certificate to the organization's known certs.
+	srcCertPath := path.Join(tree.SignCerts, node.CommonName+"-cert.pem")
+	targetCertPath := path.Join(c.KnownCerts, node.CommonName+"-cert.pem")
+	err = copyFile(srcCertPath, targetCertPath)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeSummary{CommonName: node.CommonName, OU: p.OU, MSPDir: tree.MSP}, nil
+}
+
 func copyFile(src, dst string) error {
 	content, err := os.ReadFile(src)
 	if err != nil {
@@ -7,7 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package cryptogen
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
@@ -15,6 +20,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/hyperledger/fabric-x-common/api/msppb"
 	"github.com/hyperledger/fabric-x-common/msp"
 	"github.com/hyperledger/fabric-x-common/sampleconfig"
 	"github.com/hyperledger/fabric-x-common/tools/test"
@@ -207,3 +213,260 @@ func defaultConfig(enableNodeOUs bool) *Config {
 		},
 	}
 }
+
+// peerOrgConfig returns a config with a single peer organization, which has no nodes.
+func peerOrgConfig(enableNodeOUs bool) *Config {
+	return &Config{
+		PeerOrgs: []OrgSpec{{
+			Name:          "PeerOrg",
+			Domain:        "peer-org.com",
+			EnableNodeOUs: enableNodeOUs,
+			CA: NodeSpec{
+				Hostname: "ca.peer-org.com", CommonName: "PeerOrgCA", PublicKeyAlgorithm: ECDSA,
+			},
+		}},
+	}
+}
+
+func TestImportUsers(t *testing.T) {
+	t.Parallel()
+	for _, nodeOUs := range []bool{true, false} {
+		t.Run(fmt.Sprintf("nodeOUs=%t", nodeOUs), func(t *testing.T) {
+			t.Parallel()
+			testDir := t.TempDir()
+			require.NoError(t, Generate(testDir, peerOrgConfig(nodeOUs)))
+			orgPath := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com")
+
+			// Mint a user certificate with the org's CA, out of band.
+			signCA, err := loadCA(filepath.Join(orgPath, CaDir), &OrgSpec{}, "PeerOrgCA")
+			require.NoError(t, err)
+			certFile, keyFile := newImportedUser(t, signCA)
+
+			conf := peerOrgConfig(nodeOUs)
+			conf.PeerOrgs[0].Users.Specs = []UserSpec{{Name: "migrated", CertFile: certFile, KeyFile: keyFile}}
+			require.NoError(t, Extend(testDir, conf))
+
+			userMSPDir := filepath.Join(orgPath, UsersDir, "migrated@peer-org.com", MSPDir)
+			localMsp, err := msp.LoadLocalMspDir(msp.DirLoadParameters{MspDir: userMSPDir})
+			require.NoError(t, err)
+			require.NotNil(t, localMsp)
+
+			importedCert, err := loadCertificateFile(certFile)
+			require.NoError(t, err)
+			userCert, err := loadCertificate(filepath.Join(userMSPDir, SignCertsDir))
+			require.NoError(t, err)
+			require.True(t, importedCert.Equal(userCert))
+			require.FileExists(t, x509FilePath(orgPath, MSPDir, KnownCertsDir, "migrated@peer-org.com"))
+		})
+	}
+
+	t.Run("external trust anchor", func(t *testing.T) {
+		t.Parallel()
+		testDir := t.TempDir()
+		require.NoError(t, Generate(testDir, peerOrgConfig(false)))
+		orgPath := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com")
+
+		externalCADir := filepath.Join(testDir, "external-ca")
+		externalCA := defaultCA(t, "ExternalCA", externalCADir)
+		certFile, keyFile := newImportedUser(t, externalCA)
+
+		conf := peerOrgConfig(false)
+		conf.PeerOrgs[0].Users.Specs = []UserSpec{{Name: "migrated", CertFile: certFile, KeyFile: keyFile}}
+		err := Extend(testDir, conf)
+		require.ErrorContains(t, err, "does not chain to the org CA or a trust anchor")
+
+		conf = peerOrgConfig(false)
+		conf.PeerOrgs[0].Users.Specs = []UserSpec{{Name: "migrated", CertFile: certFile, KeyFile: keyFile}}
+		conf.PeerOrgs[0].Users.TrustAnchors = []string{x509FilePath(externalCADir, "ExternalCA")}
+		require.NoError(t, Extend(testDir, conf))
+
+		userMSPDir := filepath.Join(orgPath, UsersDir, "migrated@peer-org.com", MSPDir)
+		_, err = msp.LoadLocalMspDir(msp.DirLoadParameters{MspDir: userMSPDir})
+		require.NoError(t, err)
+		_, err = msp.LoadVerifyingMspDir(msp.DirLoadParameters{MspDir: filepath.Join(orgPath, MSPDir)})
+		require.NoError(t, err)
+	})
+
+	t.Run("missing key file", func(t *testing.T) {
+		t.Parallel()
+		testDir := t.TempDir()
+		conf := peerOrgConfig(false)
+		conf.PeerOrgs[0].Users.Specs = []UserSpec{{Name: "migrated", CertFile: "cert.pem"}}
+		err := Generate(testDir, conf)
+		require.ErrorContains(t, err, "a key file must be provided with the certificate file")
+	})
+}
+
+func newImportedUser(t *testing.T, ca *caParams) (certFile, keyFile string) {
+	t.Helper()
+	userDir := t.TempDir()
+	priv, err := generatePrivateKey(userDir, ECDSA, "")
+	require.NoError(t, err)
+	_, err = ca.signCertificate(userDir, "migrated", signCertParams{
+		OrgUnits:  []string{ClientOU},
+		KeyUsage:  x509.KeyUsageDigitalSignature,
+		PublicKey: getPublicKey(priv),
+	})
+	require.NoError(t, err)
+	return x509FilePath(userDir, "migrated"), filepath.Join(userDir, PrivateKeyFile)
+}
+
+func TestGenerateCAOnly(t *testing.T) {
+	t.Parallel()
+	for _, nodeOUs := range []bool{true, false} {
+		t.Run(fmt.Sprintf("nodeOUs=%t", nodeOUs), func(t *testing.T) {
+			t.Parallel()
+			testDir := t.TempDir()
+			conf := peerOrgConfig(nodeOUs)
+			conf.PeerOrgs[0].CAOnly = true
+			conf.PeerOrgs[0].Specs = []NodeSpec{{Hostname: "peer0"}}
+			require.NoError(t, Generate(testDir, conf))
+
+			orgPath := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com")
+			test.RequireTree(t, orgPath, nil, []string{MSPDir, CaDir, TLSCaDir})
+			require.NoDirExists(t, filepath.Join(orgPath, PeerNodesDir))
+			require.NoDirExists(t, filepath.Join(orgPath, UsersDir))
+			_, err := msp.LoadVerifyingMspDir(msp.DirLoadParameters{MspDir: filepath.Join(orgPath, MSPDir)})
+			require.NoError(t, err)
+
+			conf.PeerOrgs[0].CAOnly = false
+			require.NoError(t, Extend(testDir, conf))
+
+			peerMSPDir := filepath.Join(orgPath, PeerNodesDir, "peer0.peer-org.com", MSPDir)
+			_, err = msp.LoadLocalMspDir(msp.DirLoadParameters{MspDir: peerMSPDir})
+			require.NoError(t, err)
+			require.DirExists(t, filepath.Join(orgPath, UsersDir, "Admin@peer-org.com"))
+			verifyingMsp, err := msp.LoadVerifyingMspDir(msp.DirLoadParameters{MspDir: filepath.Join(orgPath, MSPDir)})
+			require.NoError(t, err)
+
+			// The peer is valid for the organization's verifying MSP.
+			peerCert, err := os.ReadFile(x509FilePath(peerMSPDir, SignCertsDir, "peer0.peer-org.com"))
+			require.NoError(t, err)
+			mspID, err := verifyingMsp.GetIdentifier()
+			require.NoError(t, err)
+			peerID, err := verifyingMsp.DeserializeIdentity(msppb.NewIdentity(mspID, peerCert))
+			require.NoError(t, err)
+			require.NoError(t, peerID.Validate())
+		})
+	}
+}
+
+func TestGenerateWithSummary(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	conf := peerOrgConfig(true)
+	conf.PeerOrgs[0].Specs = []NodeSpec{{Hostname: "peer0"}}
+	conf.PeerOrgs[0].Users.Count = 1
+	summary, err := GenerateWithSummary(testDir, conf)
+	require.NoError(t, err)
+
+	// The summary round-trips through JSON.
+	summaryJSON, err := json.Marshal(summary)
+	require.NoError(t, err)
+	var decoded Summary
+	require.NoError(t, json.Unmarshal(summaryJSON, &decoded))
+	require.Equal(t, summary, &decoded)
+
+	require.Len(t, decoded.Organizations, 1)
+	org := decoded.Organizations[0]
+	require.Equal(t, "PeerOrg", org.Name)
+	require.Equal(t, "peer-org.com", org.Domain)
+	require.Equal(t, filepath.Join(PeerOrganizationsDir, "peer-org.com"), org.Dir)
+	for caDir, fingerprint := range map[string]string{CaDir: org.CAFingerprint, TLSCaDir: org.TLSCAFingerprint} {
+		caCert, err := loadCertificate(filepath.Join(testDir, org.Dir, caDir))
+		require.NoError(t, err)
+		digest := sha256.Sum256(caCert.Raw)
+		require.Equal(t, hex.EncodeToString(digest[:]), fingerprint, caDir)
+	}
+
+	require.Equal(t, []NodeSummary{
+		{
+			CommonName: "peer0.peer-org.com",
+			OU:         PeerOU,
+			MSPDir:     filepath.Join(org.Dir, PeerNodesDir, "peer0.peer-org.com", MSPDir),
+		},
+		{
+			CommonName: "User1@peer-org.com",
+			OU:         ClientOU,
+			MSPDir:     filepath.Join(org.Dir, UsersDir, "User1@peer-org.com", MSPDir),
+		},
+		{
+			CommonName: "Admin@peer-org.com",
+			OU:         AdminOU,
+			MSPDir:     filepath.Join(org.Dir, UsersDir, "Admin@peer-org.com", MSPDir),
+		},
+	}, org.Nodes)
+	for _, node := range org.Nodes {
+		require.DirExists(t, filepath.Join(testDir, node.MSPDir))
+	}
+}
+
+func TestGenerateNodesConcurrently(t *testing.T) {
+	t.Parallel()
+	sequentialDir := t.TempDir()
+	sequential := generateLargeOrg(t, sequentialDir, 1)
+	concurrentDir := t.TempDir()
+	concurrent := generateLargeOrg(t, concurrentDir, 0)
+
+	require.Equal(t, listFiles(t, sequentialDir), listFiles(t, concurrentDir))
+	sequentialSummary, err := sequential.relativeSummary(sequentialDir)
+	require.NoError(t, err)
+	concurrentSummary, err := concurrent.relativeSummary(concurrentDir)
+	require.NoError(t, err)
+	require.Len(t, concurrentSummary.Nodes, 52)
+	require.Equal(t, sequentialSummary.Nodes, concurrentSummary.Nodes)
+
+	verifyingMsp, err := msp.LoadVerifyingMspDir(msp.DirLoadParameters{
+		MspDir: filepath.Join(concurrentDir, concurrentSummary.Dir, MSPDir),
+	})
+	require.NoError(t, err)
+	mspID, err := verifyingMsp.GetIdentifier()
+	require.NoError(t, err)
+	for _, node := range concurrentSummary.Nodes {
+		certPEM, err := os.ReadFile(x509FilePath(concurrentDir, node.MSPDir, SignCertsDir, node.CommonName))
+		require.NoError(t, err)
+		id, err := verifyingMsp.DeserializeIdentity(msppb.NewIdentity(mspID, certPEM))
+		require.NoError(t, err)
+		require.NoError(t, id.Validate(), node.CommonName)
+	}
+}
+
+func BenchmarkGenerateNodes(b *testing.B) {
+	for _, parallelism := range []int{1, 0} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			for range b.N {
+				generateLargeOrg(b, b.TempDir(), parallelism)
+			}
+		})
+	}
+}
+
+// generateLargeOrg generates an organization of 50 peers and a user in rootDir,
+// with up to parallelism nodes generated concurrently.
+func generateLargeOrg(tb testing.TB, rootDir string, parallelism int) *orgCryptoTree {
+	tb.Helper()
+	conf := peerOrgConfig(true)
+	conf.PeerOrgs[0].Template.Count = 50
+	conf.PeerOrgs[0].Users.Count = 1
+	c, err := prepareAllCryptoSpecs(rootDir, conf)
+	require.NoError(tb, err)
+	orgTree := c.PeerOrgs[0]
+	orgTree.Parallelism = parallelism
+	require.NoError(tb, orgTree.generateOrg())
+	return orgTree
+}
+
+// listFiles returns the paths of all the files and directories under root, relative to it.
+func listFiles(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	require.NoError(t, filepath.WalkDir(root, func(p string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		files = append(files, rel)
+		return err
+	}))
+	return files
+}
@@ -8,6 +8,10 @@ package cryptogen
 
 import (
 	"bytes"
+	"net"
+	"net/mail"
+	"slices"
+	"strings"
 	"text/template"
 
 	"github.com/cockroachdb/errors"
@@ -37,6 +41,8 @@ func renderOrgSpecForOrgUnitWithTemplate(orgSpec *OrgSpec, orgUnit string) error
 func renderOrgSpec(orgSpec *OrgSpec) error {
 	// Touch up all general node-specs to add the domain
 	for i := range orgSpec.Specs {
+		// Specs rendered from the template share its SANS slice, so the common SANs are added to a copy.
+		orgSpec.Specs[i].SANS = slices.Concat(orgSpec.Specs[i].SANS, orgSpec.CommonSANs)
 		err := renderNodeSpec(orgSpec.Domain, &orgSpec.Specs[i])
 		if err != nil {
 			return err
@@ -79,6 +85,7 @@ func renderNodeTemplate(orgSpec *OrgSpec, orgUnit string) error {
 			CommonName:         hostname,
 			SANS:               orgSpec.Template.SANS,
 			PublicKeyAlgorithm: publicKeyAlg,
+			ECDSACurve:         orgSpec.Template.ECDSACurve,
 			OrganizationalUnit: orgUnit,
 		})
 	}
@@ -120,10 +127,69 @@ func renderNodeSpec(domain string, spec *NodeSpec) error {
 		}
 		spec.SANS = append(spec.SANS, san)
 	}
+	spec.SANS = dedupSANs(spec.SANS)
 
+	if spec.EmailSANs, err = parseTemplates(spec.EmailSANs, data); err != nil {
+		return err
+	}
+	if spec.URISANs, err = parseTemplates(spec.URISANs, data); err != nil {
+		return err
+	}
+
+	if err = validateEmail(spec); err != nil {
+		return err
+	}
+	return validateEmailAndURISANs(spec)
+}
+
+// validateEmailAndURISANs fails if an email SAN is not a plain email address, or a URI SAN is not an absolute URI.
+func validateEmailAndURISANs(spec *NodeSpec) error {
+	for _, email := range spec.EmailSANs {
+		addr, err := mail.ParseAddress(email)
+		if err != nil || addr.Address != email {
+			return errors.Newf("invalid email SAN %q of %s", email, spec.CommonName)
+		}
+	}
+	for _, uri := range spec.URISANs {
+		if _, err := parseURISAN(uri); err != nil {
+			return errors.Wrapf(err, "invalid SANs of %s", spec.CommonName)
+		}
+	}
 	return nil
 }
 
+// validateEmail fails if the email of the spec is set, but is not a plain email address.
+func validateEmail(spec *NodeSpec) error {
+	if spec.Email == "" {
+		return nil
+	}
+	addr, err := mail.ParseAddress(spec.Email)
+	if err != nil || addr.Address != spec.Email {
+		return errors.Newf("invalid email %q of %s", spec.Email, spec.CommonName)
+	}
+	return nil
+}
+
+// dedupSANs removes repeated SANS entries, keeping the first occurrence.
+// IP addresses are compared by value, so different notations of the same IP are detected,
+// and DNS names are compared case-insensitively.
+func dedupSANs(sans []string) []string {
+	seen := make(map[string]struct{}, len(sans))
+	deduped := sans[:0]
+	for _, san := range sans {
+		key := strings.ToLower(san)
+		if ip := net.ParseIP(san); ip != nil {
+			key = ip.String()
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, san)
+	}
+	return deduped
+}
+
 func parseTemplate(input string, data any) (string, error) {
 	t, err := template.New("parse").Parse(input)
 	if err != nil {
@@ -139,6 +205,22 @@ func parseTemplate(input string, data any) (string, error) {
 	return output.String(), nil
 }
 
+// parseTemplates returns a new slice with each of the inputs rendered with the given data.
+func parseTemplates(inputs []string, data any) ([]string, error) {
+	if inputs == nil {
+		return nil, nil
+	}
+	outputs := make([]string, len(inputs))
+	for i, input := range inputs {
+		output, err := parseTemplate(input, data)
+		if err != nil {
+			return nil, err
+		}
+		outputs[i] = output
+	}
+	return outputs, nil
+}
+
 func parseTemplateWithDefault(input, defaultInput string, data any) (string, error) {
 	// Use the default if the input is an empty string
 	if len(input) == 0 {
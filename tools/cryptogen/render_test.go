@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptogen
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCommonSANs(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	conf := peerOrgConfig(false)
+	org := &conf.PeerOrgs[0]
+	org.Template = NodeTemplate{Count: 2, SANS: []string{"{{.Hostname}}.alt.{{.Domain}}"}}
+	org.Specs = []NodeSpec{{Hostname: "committer", SANS: []string{"10.0.0.1"}}}
+	org.CommonSANs = []string{"peers.svc.cluster.local", "10.0.0.1", "::1"}
+	require.NoError(t, Generate(testDir, conf))
+
+	peersDir := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com", PeerNodesDir)
+	nodeDirs, err := os.ReadDir(peersDir)
+	require.NoError(t, err)
+	require.Len(t, nodeDirs, 3)
+	for _, nodeDir := range nodeDirs {
+		cert, err := loadCertificateFile(filepath.Join(peersDir, nodeDir.Name(), TLSDir, ServerPrefix+".crt"))
+		require.NoError(t, err)
+		require.Contains(t, cert.DNSNames, "peers.svc.cluster.local", nodeDir.Name())
+		require.Contains(t, cert.DNSNames, nodeDir.Name(), nodeDir.Name())
+		// An IP given both per node and in common is only added once.
+		require.Len(t, cert.IPAddresses, 2, nodeDir.Name())
+		require.True(t, cert.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")), nodeDir.Name())
+		require.True(t, cert.IPAddresses[1].Equal(net.IPv6loopback), nodeDir.Name())
+	}
+}
+
+func TestGenerateEmail(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	conf := peerOrgConfig(false)
+	conf.PeerOrgs[0].CA.Email = "ca@peer-org.com"
+	conf.PeerOrgs[0].Specs = []NodeSpec{{Hostname: "peer0", Email: "peer0@peer-org.com"}}
+	require.NoError(t, Generate(testDir, conf))
+
+	orgPath := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com")
+	caCert, err := loadCertificate(filepath.Join(orgPath, CaDir))
+	require.NoError(t, err)
+	require.Equal(t, []string{"ca@peer-org.com"}, caCert.EmailAddresses)
+	tlsCACert, err := loadCertificate(filepath.Join(orgPath, TLSCaDir))
+	require.NoError(t, err)
+	require.Equal(t, []string{"ca@peer-org.com"}, tlsCACert.EmailAddresses)
+
+	peerDir := filepath.Join(orgPath, PeerNodesDir, "peer0.peer-org.com")
+	signCert, err := loadCertificate(filepath.Join(peerDir, MSPDir, SignCertsDir))
+	require.NoError(t, err)
+	require.Equal(t, []string{"peer0@peer-org.com"}, signCert.EmailAddresses)
+	tlsCert, err := loadCertificateFile(filepath.Join(peerDir, TLSDir, ServerPrefix+".crt"))
+	require.NoError(t, err)
+	require.Empty(t, tlsCert.EmailAddresses)
+
+	for _, email := range []string{"peer0", "Peer <peer0@peer-org.com>", "peer0@"} {
+		conf = peerOrgConfig(false)
+		conf.PeerOrgs[0].Specs = []NodeSpec{{Hostname: "peer0", Email: email}}
+		err = Generate(t.TempDir(), conf)
+		require.EqualError(t, err, fmt.Sprintf("invalid email %q of peer0.peer-org.com", email))
+	}
+}
+
+func TestGenerateEmailAndURISANs(t *testing.T) {
+	t.Parallel()
+	testDir := t.TempDir()
+	conf := peerOrgConfig(false)
+	conf.PeerOrgs[0].Specs = []NodeSpec{{
+		Hostname:  "peer0",
+		SANS:      []string{"*.{{.Domain}}"},
+		EmailSANs: []string{"{{.Hostname}}@{{.Domain}}"},
+		URISANs:   []string{"spiffe://{{.Domain}}/{{.Hostname}}"},
+	}}
+	require.NoError(t, Generate(testDir, conf))
+
+	peerDir := filepath.Join(testDir, PeerOrganizationsDir, "peer-org.com", PeerNodesDir, "peer0.peer-org.com")
+	tlsCert, err := loadCertificateFile(filepath.Join(peerDir, TLSDir, ServerPrefix+".crt"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"peer0.peer-org.com", "peer0", "*.peer-org.com"}, tlsCert.DNSNames)
+	require.Equal(t, []string{"peer0@peer-org.com"}, tlsCert.EmailAddresses)
+	require.Len(t, tlsCert.URIs, 1)
+	require.Equal(t, "spiffe://peer-org.com/peer0", tlsCert.URIs[0].String())
+
+	// The email and URI SANs are only added to the TLS certificate.
+	signCert, err := loadCertificate(filepath.Join(peerDir, MSPDir, SignCertsDir))
+	require.NoError(t, err)
+	require.Empty(t, signCert.EmailAddresses)
+	require.Empty(t, signCert.URIs)
+
+	conf = peerOrgConfig(false)
+	conf.PeerOrgs[0].Specs = []NodeSpec{{Hostname: "peer0", EmailSANs: []string{"peer0"}}}
+	err = Generate(t.TempDir(), conf)
+	require.EqualError(t, err, `invalid email SAN "peer0" of peer0.peer-org.com`)
+
+	conf = peerOrgConfig(false)
+	conf.PeerOrgs[0].Specs = []NodeSpec{{Hostname: "peer0", URISANs: []string{"/peer0"}}}
+	err = Generate(t.TempDir(), conf)
+	require.EqualError(t, err, `invalid SANs of peer0.peer-org.com: invalid URI SAN "/peer0": missing scheme`)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptogen
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Serial number strategies of a CA.
+const (
+	// SerialNumberRandom issues random 128-bit serial numbers. It is the default.
+	SerialNumberRandom = "random"
+	// SerialNumberMonotonic issues increasing serial numbers. They start from the generation time
+	// in nanoseconds, so that the serial numbers keep increasing when the CA is loaded again by Extend.
+	SerialNumberMonotonic = "monotonic"
+)
+
+// serialNumberGenerator generates the serial numbers of the certificates issued by a CA.
+// The serial numbers it generates are unique.
+type serialNumberGenerator interface {
+	nextSerialNumber() (*big.Int, error)
+}
+
+// newSerialNumberGenerator returns a generator for the given strategy, or the random one if empty.
+func newSerialNumberGenerator(strategy string) (serialNumberGenerator, error) {
+	switch strategy {
+	case "", SerialNumberRandom:
+		return &randomSerialNumbers{issued: make(map[string]struct{})}, nil
+	case SerialNumberMonotonic:
+		return &monotonicSerialNumbers{last: big.NewInt(time.Now().UnixNano())}, nil
+	default:
+		return nil, errors.Newf("unsupported serial number strategy '%s'", strategy)
+	}
+}
+
+func isSupportedSerialNumberStrategy(strategy string) bool {
+	_, err := newSerialNumberGenerator(strategy)
+	return err == nil
+}
+
+// randomSerialNumbers generates random 128-bit serial numbers, and retries on the unlikely repetition.
+type randomSerialNumbers struct {
+	mu     sync.Mutex
+	issued map[string]struct{}
+}
+
+func (g *randomSerialNumbers) nextSerialNumber() (*big.Int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for {
+		serialNumber, err := randomSerialNumber()
+		if err != nil {
+			return nil, err
+		}
+		key := string(serialNumber.Bytes())
+		if _, ok := g.issued[key]; !ok {
+			g.issued[key] = struct{}{}
+			return serialNumber, nil
+		}
+	}
+}
+
+// monotonicSerialNumbers generates increasing serial numbers.
+type monotonicSerialNumbers struct {
+	mu   sync.Mutex
+	last *big.Int
+}
+
+func (g *monotonicSerialNumbers) nextSerialNumber() (*big.Int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.last = new(big.Int).Add(g.last, big.NewInt(1))
+	return new(big.Int).Set(g.last), nil
+}
+
+// randomSerialNumber returns a random 128-bit serial number.
+func randomSerialNumber() (*big.Int, error) {
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	return serialNumber, errors.Wrap(err, "failed to generate a serial number")
+}
@@ -0,0 +1,163 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptogen
+
+import (
+	"path"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ValidateConfig checks the config for problems that would make the generation fail or produce
+// conflicting material, and returns all of them at once:
+// duplicate organization names, organizations without a domain, duplicate node common names
+// within an organization, unsupported key algorithms and serial number strategies, and negative expiries.
+func ValidateConfig(config *Config) []error {
+	var errs []error
+	orgNames := make(map[string]struct{})
+	for _, orgs := range []struct {
+		specs   []OrgSpec
+		orgUnit string
+	}{
+		{specs: config.OrdererOrgs, orgUnit: OrdererOU},
+		{specs: config.PeerOrgs, orgUnit: PeerOU},
+		{specs: config.GenericOrgs},
+	} {
+		for i := range orgs.specs {
+			org := &orgs.specs[i]
+			if _, ok := orgNames[org.Name]; ok {
+				errs = append(errs, errors.Newf("duplicate organization name '%s'", org.Name))
+			}
+			orgNames[org.Name] = struct{}{}
+			errs = append(errs, validateOrgSpec(org, orgs.orgUnit)...)
+		}
+	}
+	return errs
+}
+
+// keyAlgorithmUse is a key algorithm set in an organization spec, along with what it applies to.
+type keyAlgorithmUse struct {
+	owner     string
+	algorithm string
+}
+
+func validateOrgSpec(org *OrgSpec, orgUnit string) []error {
+	var errs []error
+	if org.Domain == "" {
+		errs = append(errs, errors.Newf("organization '%s' has no domain", org.Name))
+	}
+
+	if !isSupportedKeyAlgorithm(getPublicKeyAlg(org.CA.PublicKeyAlgorithm)) {
+		errs = append(errs, errors.Newf("organization '%s' has an unsupported key algorithm '%s' for the CA",
+			org.Name, org.CA.PublicKeyAlgorithm))
+	}
+	// The MSP cannot load RSA signing identities, so RSA keys are only supported for CAs.
+	keyAlgorithms := []keyAlgorithmUse{
+		{owner: "the node template", algorithm: org.Template.PublicKeyAlgorithm},
+		{owner: "the users", algorithm: org.Users.PublicKeyAlgorithm},
+	}
+	for i := range org.Specs {
+		keyAlgorithms = append(keyAlgorithms, keyAlgorithmUse{
+			owner: "node '" + org.Specs[i].Hostname + "'", algorithm: org.Specs[i].PublicKeyAlgorithm,
+		})
+	}
+	for _, u := range org.Users.Specs {
+		keyAlgorithms = append(keyAlgorithms, keyAlgorithmUse{owner: "user '" + u.Name + "'", algorithm: u.PublicKeyAlgorithm})
+	}
+	for _, k := range keyAlgorithms {
+		switch keyAlg := getPublicKeyAlg(k.algorithm); {
+		case isRSAKeyAlgorithm(keyAlg):
+			errs = append(errs, errors.Newf("organization '%s' has an RSA key algorithm '%s' for %s, "+
+				"but RSA keys are only supported for the CA", org.Name, k.algorithm, k.owner))
+		case !isSupportedKeyAlgorithm(keyAlg):
+			errs = append(errs, errors.Newf("organization '%s' has an unsupported key algorithm '%s' for %s",
+				org.Name, k.algorithm, k.owner))
+		}
+	}
+
+	if !isSupportedSerialNumberStrategy(org.CA.SerialNumberStrategy) {
+		errs = append(errs, errors.Newf("organization '%s' has an unsupported serial number strategy '%s'",
+			org.Name, org.CA.SerialNumberStrategy))
+	}
+	errs = append(errs, validateExpiries(org)...)
+	return append(errs, validateNodeNames(org, orgUnit)...)
+}
+
+// validateExpiries checks that the certificate validity periods of the CA and the nodes are not negative.
+func validateExpiries(org *OrgSpec) []error {
+	var errs []error
+	if org.CA.Expiry < 0 {
+		errs = append(errs, errors.Newf("organization '%s' has a negative expiry %s for the CA", org.Name, org.CA.Expiry))
+	}
+	for i := range org.Specs {
+		if s := &org.Specs[i]; s.Expiry < 0 {
+			errs = append(errs, errors.Newf("organization '%s' has a negative expiry %s for node '%s'",
+				org.Name, s.Expiry, s.Hostname))
+		}
+	}
+	return errs
+}
+
+// validateNodeNames checks that no two nodes of the organization, including the templated ones,
+// resolve to the same common name, and would thus share the same directory.
+func validateNodeNames(org *OrgSpec, orgUnit string) []error {
+	var names []string
+	// Generic organizations do not render templates.
+	if orgUnit != "" {
+		for i := range org.Template.Count {
+			hostname, err := parseTemplateWithDefault(org.Template.Hostname, defaultHostnameTemplate, hostnameData{
+				Prefix: orgUnit,
+				Index:  i + org.Template.Start,
+				Domain: org.Domain,
+			})
+			if err != nil {
+				return []error{errors.Wrapf(err, "organization '%s' has an invalid node template", org.Name)}
+			}
+			names = append(names, hostname)
+		}
+	}
+	for i := range org.Specs {
+		s := &org.Specs[i]
+		cn, err := parseTemplateWithDefault(s.CommonName, defaultCNTemplate, specData{
+			Hostname:   s.Hostname,
+			CommonName: s.CommonName,
+			Domain:     org.Domain,
+		})
+		if err != nil {
+			return []error{errors.Wrapf(err, "organization '%s' has an invalid node spec", org.Name)}
+		}
+		names = append(names, path.Join(s.Party, cn))
+	}
+
+	var errs []error
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			errs = append(errs, errors.Newf("organization '%s' has duplicate node common name '%s'", org.Name, name))
+		}
+		seen[name] = struct{}{}
+	}
+	return errs
+}
+
+func isRSAKeyAlgorithm(keyAlg string) bool {
+	switch keyAlg {
+	case RSA, RSA2048, RSA4096:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSupportedKeyAlgorithm(keyAlg string) bool {
+	switch keyAlg {
+	case ECDSA, ED25519, RSA, RSA2048, RSA4096:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cryptogen
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	t.Parallel()
+	require.Empty(t, ValidateConfig(peerOrgConfig(true)))
+
+	conf := &Config{
+		OrdererOrgs: []OrgSpec{{
+			Name:   "Org1",
+			Domain: "org1.com",
+			// The templated nodes are named orderer0 and orderer1.
+			Template: NodeTemplate{Count: 2},
+			Specs: []NodeSpec{
+				{Hostname: "first", CommonName: "orderer0"},
+				{Hostname: "orderer1", Party: "party1"},
+				{Hostname: "other", CommonName: "orderer1.org1.com", Party: "party1"},
+			},
+		}},
+		PeerOrgs: []OrgSpec{{
+			Name:  "Org1",
+			CA:    NodeSpec{PublicKeyAlgorithm: "rsa1024", SerialNumberStrategy: "sequential"},
+			Specs: []NodeSpec{{Hostname: "peer0", PublicKeyAlgorithm: "rsa4096"}},
+			Users: UsersSpec{
+				Specs: []UserSpec{{Name: "alice", PublicKeyAlgorithm: "dsa"}},
+			},
+		}},
+	}
+	errs := ValidateConfig(conf)
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	require.Equal(t, []string{
+		"organization 'Org1' has duplicate node common name 'orderer0'",
+		"organization 'Org1' has duplicate node common name 'party1/orderer1.org1.com'",
+		"duplicate organization name 'Org1'",
+		"organization 'Org1' has no domain",
+		"organization 'Org1' has an unsupported key algorithm 'rsa1024' for the CA",
+		"organization 'Org1' has an RSA key algorithm 'rsa4096' for node 'peer0', " +
+			"but RSA keys are only supported for the CA",
+		"organization 'Org1' has an unsupported key algorithm 'dsa' for user 'alice'",
+		"organization 'Org1' has an unsupported serial number strategy 'sequential'",
+	}, messages)
+
+	err := Generate(t.TempDir(), conf)
+	require.ErrorContains(t, err, "invalid config: organization 'Org1' has duplicate node common name")
+	require.ErrorContains(t, err, "organization 'Org1' has an unsupported key algorithm 'dsa' for user 'alice'")
+	err = Extend(t.TempDir(), conf)
+	require.ErrorContains(t, err, "invalid config")
+}
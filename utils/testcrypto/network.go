@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testcrypto
+
+import (
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+	"github.com/hyperledger/fabric-lib-go/bccsp/factory"
+	"github.com/hyperledger/fabric-protos-go-apiv2/common"
+
+	"github.com/hyperledger/fabric-x-common/common/channelconfig"
+	"github.com/hyperledger/fabric-x-common/msp"
+	"github.com/hyperledger/fabric-x-common/tools/cryptogen"
+)
+
+// TestNetwork is the crypto material and the config block of a network, generated together for tests.
+type TestNetwork struct {
+	// CryptoPath is the root of the crypto-config tree.
+	CryptoPath string
+	// ConfigBlockPath is the path of the config block, within CryptoPath.
+	ConfigBlockPath string
+	ConfigBlock     *common.Block
+	Bundle          *channelconfig.Bundle
+	// PeerSigners are the signing identities of the client users of the peer organizations.
+	PeerSigners []msp.SigningIdentity
+	// ConsenterSigners are the signing identities of the consenters of the orderer organizations.
+	ConsenterSigners []msp.SigningIdentity
+}
+
+// GenerateTestNetwork generates the crypto material and the config block of a network
+// in targetPath, as CreateOrExtendConfigBlockWithCrypto does,
+// and loads the channel config bundle and the signing identities.
+func GenerateTestNetwork(targetPath string, conf *ConfigBlock) (*TestNetwork, error) {
+	n := &TestNetwork{
+		CryptoPath:      targetPath,
+		ConfigBlockPath: filepath.Join(targetPath, cryptogen.ConfigBlockFileName),
+	}
+
+	var err error
+	n.ConfigBlock, err = CreateOrExtendConfigBlockWithCrypto(n.CryptoPath, conf)
+	if err != nil {
+		return nil, err
+	}
+	n.Bundle, err = channelconfig.NewBundleFromBlock(n.ConfigBlock, factory.GetDefault())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the config block")
+	}
+	n.PeerSigners, err = GetPeersIdentities(n.CryptoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the peer identities")
+	}
+	n.ConsenterSigners, err = GetConsenterIdentities(n.CryptoPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the consenter identities")
+	}
+	return n, nil
+}
@@ -632,3 +632,45 @@ func TestSnapshotCheckpointPoliciesResolve(t *testing.T) {
 	require.True(t, ok, "CheckpointEndorsement policy not found")
 	require.NotNil(t, checkpointPolicy)
 }
+
+func TestGenerateTestNetwork(t *testing.T) {
+	t.Parallel()
+	n, err := GenerateTestNetwork(t.TempDir(), &ConfigBlock{
+		ChannelID:             "test-channel",
+		PeerOrganizationCount: 2,
+		OrdererEndpoints: []*types.OrdererEndpoint{
+			{ID: 0, Host: "localhost", Port: 7050},
+			{ID: 1, Host: "localhost", Port: 7051},
+		},
+	})
+	require.NoError(t, err)
+
+	require.FileExists(t, n.ConfigBlockPath)
+	require.DirExists(t, filepath.Join(n.CryptoPath, cryptogen.PeerOrganizationsDir))
+	require.Equal(t, "test-channel", n.Bundle.ConfigtxValidator().ChannelID())
+	require.NoError(t, n.Bundle.ValidateNew(n.Bundle))
+	require.Len(t, n.PeerSigners, 2)
+	require.Len(t, n.ConsenterSigners, 2)
+
+	requireSatisfies(t, n.Bundle, "/Channel/Application/Writers", n.PeerSigners)
+	requireSatisfies(t, n.Bundle, "/Channel/Orderer/BlockValidation", n.ConsenterSigners)
+}
+
+func requireSatisfies(t *testing.T, bundle *channelconfig.Bundle, policyName string, signers []msp.SigningIdentity) {
+	t.Helper()
+	policy, ok := bundle.PolicyManager().GetPolicy(policyName)
+	require.Truef(t, ok, "policy %s not found", policyName)
+
+	data := []byte("data")
+	signedData := make([]*protoutil.SignedData, len(signers))
+	for i, signer := range signers {
+		serialized, err := signer.Serialize()
+		require.NoError(t, err)
+		id, err := protoutil.UnmarshalIdentity(serialized)
+		require.NoError(t, err)
+		sig, err := signer.Sign(data)
+		require.NoError(t, err)
+		signedData[i] = &protoutil.SignedData{Data: data, Identity: id, Signature: sig}
+	}
+	require.NoError(t, policy.EvaluateSignedData(signedData))
+}